@@ -0,0 +1,185 @@
+package go_excel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 合计行第一个不汇总的列显示的名称
+const totalsLabel = "合计"
+
+// 汇总函数，code 为 SUBTOTAL 的函数编号，忽略筛选隐藏的行，table 为表格内置合计行的函数名。
+// 字段使用 excel:"金额,agg=sum" 时在数据下方写入合计行，如 =SUBTOTAL(109,C3:C500)
+var aggregates = map[string]struct {
+	code  int
+	table string
+}{
+	"sum":   {109, "sum"},
+	"avg":   {101, "average"},
+	"count": {103, "count"},
+	"min":   {105, "min"},
+	"max":   {104, "max"},
+}
+
+// WithTableTotals 使用表格内置的合计行，公式引用表格的列，默认合计行写在表格下方
+func WithTableTotals() Option {
+	return optionFunc(func(options *Options) {
+		options.TableTotals = true
+	})
+}
+
+// 检查标签 agg 的汇总函数
+func checkAggregate(agg string) error {
+	if _, ok := aggregates[agg]; !ok {
+		return fmt.Errorf("unknown aggregate %q", agg)
+	}
+	return nil
+}
+
+// 有汇总列时返回合计行显示名称的列，所有列都汇总时为 -1
+func totalsLabelColumn(cols []*Column) (int, bool) {
+	has, label := false, -1
+	for k, col := range cols {
+		switch {
+		case col.Aggregate != "":
+			has = true
+		case label == -1:
+			label = k
+		}
+	}
+	return label, has
+}
+
+// 在数据下方写入合计行，公式汇总 firstRow 到 lastRow 的数据
+func (e *Excel) writeTotals(cols []*Column, row, firstRow, lastRow int, tableName string) error {
+	label, _ := totalsLabelColumn(cols)
+	vals := make([]any, len(cols))
+	for k, col := range cols {
+		style := &excelize.Style{}
+		// 计数不使用列的数字格式
+		if col.Aggregate != "" && col.Aggregate != "count" {
+			mergeStyle(style, e.columnStyle(col))
+		}
+		mergeStyle(style, e.theme().Footer)
+		styleID, err := e.File.NewStyle(style)
+		if err != nil {
+			return err
+		}
+		cell := excelize.Cell{StyleID: styleID}
+		switch {
+		case col.Aggregate != "":
+			ref := fmt.Sprintf("%s%d:%s%d", e.colName(k), firstRow, e.colName(k), lastRow)
+			if e.Option.TableTotals {
+				ref = fmt.Sprintf("%s[%s]", tableName, tableColumnRef(col.NaturalName))
+			}
+			cell.Formula = fmt.Sprintf("SUBTOTAL(%d,%s)", aggregates[col.Aggregate].code, ref)
+		case k == label:
+			cell.Value = totalsLabel
+		}
+		vals[k] = cell
+	}
+	return e.Sw.SetRow(e.cellName(0, row), vals)
+}
+
+// 结构化引用中的列名，特殊字符用单引号转义
+func tableColumnRef(name string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(name)
+}
+
+// 将表格下方的合计行设为表格内置的合计行，excelize 的表格不支持合计行，直接修改表格的 XML
+func (e *Excel) addTableTotals(cols []*Column, tableName, ref, totalsRef string) error {
+	var path string
+	var content []byte
+	e.File.Pkg.Range(func(key, value any) bool {
+		name, _ := key.(string)
+		data, _ := value.([]byte)
+		if strings.HasPrefix(name, "xl/tables/") && bytes.Contains(data, []byte(` name="`+tableName+`"`)) {
+			path, content = name, data
+			return false
+		}
+		return true
+	})
+	if path == "" {
+		return fmt.Errorf("table %s not found", tableName)
+	}
+	xml := string(content)
+	// 只修改表格的区域，筛选区域不包含合计行
+	xml, err := replaceOnce(xml, fmt.Sprintf(` displayName="%s" ref="%s"`, tableName, ref),
+		fmt.Sprintf(` displayName="%s" ref="%s" totalsRowCount="1"`, tableName, totalsRef))
+	if err != nil {
+		return fmt.Errorf("table %s: %w", tableName, err)
+	}
+	label, _ := totalsLabelColumn(cols)
+	for k, col := range cols {
+		var attr string
+		switch {
+		case col.Aggregate != "":
+			attr = fmt.Sprintf(` totalsRowFunction="%s"`, aggregates[col.Aggregate].table)
+		case k == label:
+			attr = fmt.Sprintf(` totalsRowLabel="%s"`, totalsLabel)
+		default:
+			continue
+		}
+		id := fmt.Sprintf(`<tableColumn id="%d"`, k+1)
+		if xml, err = replaceOnce(xml, id, id+attr); err != nil {
+			return fmt.Errorf("table %s: %w", tableName, err)
+		}
+	}
+	e.File.Pkg.Store(path, []byte(xml))
+	return nil
+}
+
+// 表格 XML 由 excelize 按固定的属性顺序生成，替换的内容必须恰好出现一次，否则说明格式已变化
+func replaceOnce(s, old, new string) (string, error) {
+	if n := strings.Count(s, old); n != 1 {
+		return "", fmt.Errorf("unexpected table xml: found %d of %s", n, old)
+	}
+	return strings.Replace(s, old, new, 1), nil
+}
+
+// 导入时识别合计行：显示名称的列为合计，其他不汇总的列为空，且汇总列是 SUBTOTAL 公式
+func (e *Excel) isTotalsRow(f *excelize.File, cols []mappedColumn, row []string, rowNum int) (bool, error) {
+	columns := make([]*Column, len(cols))
+	for i := range cols {
+		columns[i] = cols[i].Column
+	}
+	label, has := totalsLabelColumn(columns)
+	if !has {
+		return false, nil
+	}
+	agg := -1
+	for i, col := range cols {
+		var val string
+		if col.index < len(row) {
+			val = strings.TrimSpace(row[col.index])
+		}
+		switch {
+		case col.Aggregate != "":
+			if agg == -1 {
+				agg = col.index
+			}
+		case i == label:
+			if val != totalsLabel {
+				return false, nil
+			}
+		case val != "":
+			return false, nil
+		}
+	}
+	if agg == -1 {
+		return false, nil
+	}
+	// 内容与合计行相同的数据行没有公式
+	cell, err := excelize.CoordinatesToCellName(agg+1, rowNum)
+	if err != nil {
+		return false, err
+	}
+	formula, err := f.GetCellFormula(e.Option.SheetName, cell)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.TrimPrefix(formula, "="), "SUBTOTAL("), nil
+}
@@ -0,0 +1,201 @@
+package go_excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type orderTotal struct {
+	Name   string  `excel:"名称"`
+	Qty    int     `excel:"数量,agg=sum"`
+	Amount float64 `excel:"金额,format=decimal,agg=avg"`
+	Note   string  `excel:"备注,agg=count"`
+}
+
+// 读取压缩包中表格定义的 XML
+func tableXML(t *testing.T, data []byte) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range zr.File {
+		if !strings.HasPrefix(file.Name, "xl/tables/") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	t.Fatal("no table in workbook")
+	return ""
+}
+
+func TestExcel_Totals(t *testing.T) {
+	rows := []orderTotal{{"a", 1, 1.5, "x"}, {"b", 2, 2.5, ""}, {"c", 3, 3.5, "z"}}
+	tests := []struct {
+		name     string
+		opts     []Option
+		formulas map[string]string
+		table    []string // 表格 XML 中应包含的内容
+	}{
+		{
+			name: "subtotal",
+			formulas: map[string]string{
+				"B6": "SUBTOTAL(109,B3:B5)",
+				"C6": "SUBTOTAL(101,C3:C5)",
+				"D6": "SUBTOTAL(103,D3:D5)",
+			},
+			table: []string{`ref="A2:D5"`},
+		},
+		{
+			name: "table totals",
+			opts: []Option{WithTableTotals()},
+			formulas: map[string]string{
+				"B6": "SUBTOTAL(109,excel_1[数量])",
+				"C6": "SUBTOTAL(101,excel_1[金额])",
+				"D6": "SUBTOTAL(103,excel_1[备注])",
+			},
+			table: []string{
+				`ref="A2:D6" totalsRowCount="1"`,
+				`<autoFilter ref="A2:D5"`,
+				`totalsRowLabel="合计"`,
+				`totalsRowFunction="sum"`,
+				`totalsRowFunction="average"`,
+				`totalsRowFunction="count"`,
+			},
+		},
+		{
+			name: "validation rows",
+			opts: []Option{WithValidationRows(5)},
+			formulas: map[string]string{
+				"B11": "SUBTOTAL(109,B3:B10)",
+			},
+			table: []string{`ref="A2:D10"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{&DefaultOption{SheetName: "people"}}, tt.opts...)
+			data, err := Export(rows, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			for cell, want := range tt.formulas {
+				if got, _ := f.GetCellFormula("people", cell); got != want {
+					t.Errorf("formula %s = %q, want %q", cell, got, want)
+				}
+			}
+			totals := tt.formulas["B6"] != ""
+			if totals {
+				if got, _ := f.GetCellValue("people", "A6"); got != totalsLabel {
+					t.Errorf("A6 = %q, want %q", got, totalsLabel)
+				}
+				style := cellStyle(t, f, "C6")
+				if style.Font == nil || !style.Font.Bold || style.CustomNumFmt == nil || *style.CustomNumFmt != "0.00" {
+					t.Errorf("C6 style = %+v, want bold footer with number format", style)
+				}
+			}
+			xml := tableXML(t, data)
+			for _, want := range tt.table {
+				if !strings.Contains(xml, want) {
+					t.Errorf("table xml %s does not contain %s", xml, want)
+				}
+			}
+
+			// 合计行不作为数据导入
+			got, err := Import[orderTotal](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, rows) {
+				t.Errorf("Import() = %+v, want %+v", got, rows)
+			}
+		})
+	}
+}
+
+// excelize 生成的表格 XML 的属性顺序变化时，addTableTotals 返回错误而不是静默跳过
+func TestExcel_AddTableTotals(t *testing.T) {
+	e := New(&DefaultOption{SheetName: "Sheet1"})
+	if err := e.parseFields(reflect.TypeOf(orderTotal{})); err != nil {
+		t.Fatal(err)
+	}
+	e.File = excelize.NewFile()
+	defer e.File.Close()
+	if err := e.File.SetSheetRow("Sheet1", "A1", &[]string{"名称", "数量", "金额", "备注"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.File.AddTable("Sheet1", &excelize.Table{Range: "A1:D3", Name: "orders"}); err != nil {
+		t.Fatal(err)
+	}
+	cols := e.columns()
+	if err := e.addTableTotals(cols, "orders", "A1:D4", "A1:D5"); err == nil {
+		t.Error("addTableTotals() want error for unknown range")
+	}
+	if err := e.addTableTotals(cols, "orders", "A1:D3", "A1:D4"); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := e.File.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml := tableXML(t, buf.Bytes())
+	for _, want := range []string{
+		` displayName="orders" ref="A1:D4" totalsRowCount="1"`,
+		`<tableColumn id="1" totalsRowLabel="合计"`,
+		`<tableColumn id="2" totalsRowFunction="sum"`,
+		`<tableColumn id="3" totalsRowFunction="average"`,
+		`<tableColumn id="4" totalsRowFunction="count"`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("table xml %s does not contain %s", xml, want)
+		}
+	}
+}
+
+func TestExcel_ImportTotalsLabel(t *testing.T) {
+	// 名称为合计的数据行不是合计行
+	rows := []orderTotal{{"a", 1, 1, "x"}, {"合计", 5, 1, ""}}
+	for _, opts := range [][]Option{nil, {WithTableTotals()}} {
+		opts = append([]Option{&DefaultOption{SheetName: "people"}}, opts...)
+		data, err := Export(rows, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Import[orderTotal](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("Import() = %+v, want %+v", got, rows)
+		}
+	}
+}
+
+func TestColumn_Aggregate(t *testing.T) {
+	type Bad struct {
+		Qty int `excel:"数量,agg=median"`
+	}
+	if _, err := NewCodec[Bad](); err == nil {
+		t.Error("NewCodec() want error for unknown aggregate")
+	}
+}
@@ -0,0 +1,161 @@
+package go_excel
+
+import (
+	"io"
+	"iter"
+	"reflect"
+)
+
+// Codec 按结构体类型 T 导入导出，excel 标签在创建时只解析校验一次
+type Codec[T any] struct {
+	option  Options
+	fields  map[string]*Column
+	rows    map[string]*Column
+	headers map[string]*Column
+	rt      reflect.Type
+	isPtr   bool // T 是否为结构体指针
+}
+
+func NewCodec[T any](opts ...Option) (*Codec[T], error) {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	e := &Excel{Option: newOptions(opts...)}
+	if err := e.loadFields(rt); err != nil {
+		return nil, err
+	}
+	if err := e.checkRowStyler(); err != nil {
+		return nil, err
+	}
+	return &Codec[T]{
+		option:  e.Option,
+		fields:  e.Fields,
+		rows:    e.Rows,
+		headers: e.headers,
+		rt:      e.ModelRt,
+		isPtr:   rt.Kind() == reflect.Ptr,
+	}, nil
+}
+
+// 每次导入导出使用独立的 Excel，Codec 可以并发使用
+func (c *Codec[T]) excel() *Excel {
+	return &Excel{
+		Fields:  c.fields,
+		Rows:    c.rows,
+		headers: c.headers,
+		Option:  c.option,
+		ModelRt: c.rt,
+	}
+}
+
+func (c *Codec[T]) value(elem reflect.Value) T {
+	if c.isPtr {
+		return elem.Addr().Interface().(T)
+	}
+	return elem.Interface().(T)
+}
+
+func (c *Codec[T]) Export(rows []T) ([]byte, error) {
+	e := c.excel()
+	defer e.close()
+	if err := e.write(reflect.ValueOf(rows)); err != nil {
+		return nil, err
+	}
+	buf, err := e.File.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Codec[T]) ExportTo(w io.Writer, rows []T) error {
+	e := c.excel()
+	defer e.close()
+	if err := e.write(reflect.ValueOf(rows)); err != nil {
+		return err
+	}
+	return e.File.Write(w)
+}
+
+// Import 读取全部数据行，汇总模式下返回解析成功的行和 ImportErrors
+func (c *Codec[T]) Import(r io.Reader) ([]T, error) {
+	e := c.excel()
+	f, zr, err := openSource(r)
+	if err != nil {
+		return nil, err
+	}
+	e.source = zr
+	defer f.Close()
+	result := make([]T, 0)
+	err = e.readRows(f, func(elem reflect.Value, _ int) error {
+		result = append(result, c.value(elem))
+		return nil
+	})
+	return result, err
+}
+
+func Export[T any](rows []T, opts ...Option) ([]byte, error) {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Export(rows)
+}
+
+func Import[T any](r io.Reader, opts ...Option) ([]T, error) {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Import(r)
+}
+
+// ImportEach 逐行解码并回调 fn，不保留已读取的行，fn 返回 ErrStop 可提前结束
+func (c *Codec[T]) ImportEach(r io.Reader, fn func(row T, rowNum int) error) error {
+	e := c.excel()
+	f, zr, err := openSource(r)
+	if err != nil {
+		return err
+	}
+	e.source = zr
+	defer f.Close()
+	return e.readRows(f, func(elem reflect.Value, rowNum int) error {
+		return fn(c.value(elem), rowNum)
+	})
+}
+
+// ImportSeq 以迭代器的方式逐行导入，出错时产出一次错误后结束
+func (c *Codec[T]) ImportSeq(r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		stopped := false
+		err := c.ImportEach(r, func(row T, _ int) error {
+			if !yield(row, nil) {
+				stopped = true
+				return ErrStop
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+func ImportEach[T any](r io.Reader, fn func(row T, rowNum int) error, opts ...Option) error {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return err
+	}
+	return c.ImportEach(r, fn)
+}
+
+func ImportSeq[T any](r io.Reader, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		c, err := NewCodec[T](opts...)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		c.ImportSeq(r)(yield)
+	}
+}
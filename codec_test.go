@@ -0,0 +1,329 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type codecPerson struct {
+	Name string `excel:"姓名"`
+	Age  int    `excel:"年龄"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	people := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}}
+	data, err := Export(people, &DefaultOption{SheetName: "people", Title: "People"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[codecPerson](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, people) {
+		t.Errorf("Import() = %+v, want %+v", got, people)
+	}
+
+	ptrs, err := Import[*codecPerson](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 2 || *ptrs[1] != people[1] {
+		t.Errorf("Import() = %+v, want %+v", ptrs, people)
+	}
+}
+
+func TestNewCodec(t *testing.T) {
+	type Dup struct {
+		Name  string `excel:"姓名"`
+		Alias string `excel:"姓名"`
+	}
+	type NoTag struct {
+		Name string
+	}
+	tests := []struct {
+		name    string
+		newFunc func() error
+		wantErr bool
+	}{
+		{"struct", func() error { _, err := NewCodec[codecPerson](); return err }, false},
+		{"pointer", func() error { _, err := NewCodec[*codecPerson](); return err }, false},
+		{"not struct", func() error { _, err := NewCodec[int](); return err }, true},
+		{"duplicate header", func() error { _, err := NewCodec[Dup](); return err }, true},
+		{"no tag", func() error { _, err := NewCodec[NoTag](); return err }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.newFunc()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCodec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCodec_ImportEach(t *testing.T) {
+	people := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}, {Name: "Tom", Age: 30}}
+	data, err := Export(people, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		stopAt   int
+		wantRows []int
+	}{
+		{"all", 0, []int{3, 4, 5}},
+		{"stop", 4, []int{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]int, 0)
+			err := ImportEach(bytes.NewReader(data), func(row codecPerson, rowNum int) error {
+				got = append(got, rowNum)
+				if row != people[rowNum-3] {
+					t.Errorf("ImportEach() row %d = %+v, want %+v", rowNum, row, people[rowNum-3])
+				}
+				if rowNum == tt.stopAt {
+					return ErrStop
+				}
+				return nil
+			}, &DefaultOption{SheetName: "people"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("ImportEach() rows = %v, want %v", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestImportSeq(t *testing.T) {
+	people := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}, {Name: "Tom", Age: 30}}
+	data, err := Export(people, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]codecPerson, 0)
+	for row, err := range ImportSeq[codecPerson](bytes.NewReader(data), &DefaultOption{SheetName: "people"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, people[:2]) {
+		t.Errorf("ImportSeq() = %+v, want %+v", got, people[:2])
+	}
+
+	for _, err := range ImportSeq[codecPerson](bytes.NewReader(data), &DefaultOption{SheetName: "missing"}) {
+		if err == nil {
+			t.Error("ImportSeq() want error for missing sheet")
+		}
+	}
+}
+
+type nestedBase struct {
+	ID int `excel:"编号"`
+}
+
+type nestedAddress struct {
+	City   string `excel:"城市"`
+	Street string `excel:"街道"`
+}
+
+type nestedContact struct {
+	Phone string `excel:"电话"`
+}
+
+type nestedPerson struct {
+	nestedBase
+	Name    string         `excel:"姓名"`
+	Address nestedAddress  `excel:"地址"`
+	Contact *nestedContact `excel:"联系人 prefix=联系"`
+	Remark  string
+}
+
+func TestCodec_Nested(t *testing.T) {
+	c, err := NewCodec[nestedPerson]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := make([]string, 0)
+	for _, col := range c.excel().columns() {
+		headers = append(headers, col.NaturalName)
+	}
+	want := []string{"编号", "姓名", "地址-城市", "地址-街道", "联系电话"}
+	if !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %v, want %v", headers, want)
+	}
+
+	people := []nestedPerson{
+		{nestedBase{1}, "Jason", nestedAddress{"上海", "南京路"}, &nestedContact{"123"}, ""},
+		{nestedBase{2}, "Tom", nestedAddress{"北京", ""}, nil, ""},
+	}
+	data, err := c.Export(people)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Import(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, people) {
+		t.Errorf("Import() = %+v, want %+v", got, people)
+	}
+
+	type Node struct {
+		Name string `excel:"名称"`
+		Next *Node  `excel:"下级"`
+	}
+	if _, err := NewCodec[Node](); err == nil {
+		t.Error("NewCodec() want error for recursive type")
+	}
+}
+
+type groupContact struct {
+	Phone string `excel:"电话"`
+	Email string `excel:"邮箱"`
+}
+
+type groupPerson struct {
+	Name    string       `excel:"姓名"`
+	Age     int          `excel:"年龄 group=基本信息"`
+	City    string       `excel:"城市 group=基本信息/住址"`
+	Contact groupContact `excel:"联系方式 group"`
+}
+
+func TestCodec_GroupHeader(t *testing.T) {
+	people := []groupPerson{
+		{"Jason", 20, "上海", groupContact{"123", "a@b.c"}},
+		{"Tom", 30, "北京", groupContact{"456", ""}},
+	}
+	c, err := NewCodec[groupPerson](&DefaultOption{SheetName: "people", Title: "People"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Export(people)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	merged, err := f.GetMergeCells("people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranges := make([]string, 0)
+	for _, m := range merged {
+		ranges = append(ranges, m.GetStartAxis()+":"+m.GetEndAxis()+"="+m.GetCellValue())
+	}
+	want := []string{"A1:E1=People", "B2:C2=基本信息", "D2:E2=联系方式"}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("GetMergeCells() = %v, want %v", ranges, want)
+	}
+	if v, _ := f.GetCellValue("people", "C3"); v != "住址" {
+		t.Errorf("C3 = %q, want 住址", v)
+	}
+	if v, _ := f.GetCellValue("people", "D4"); v != "电话" {
+		t.Errorf("D4 = %q, want 电话", v)
+	}
+
+	got, err := c.Import(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, people) {
+		t.Errorf("Import() = %+v, want %+v", got, people)
+	}
+}
+
+func TestCodec_GroupSharedLeaf(t *testing.T) {
+	type Row struct {
+		Name      string       `excel:"姓名"`
+		Self      groupContact `excel:"本人 group"`
+		Emergency groupContact `excel:"紧急联系人 group"`
+		Remark    string       `excel:"备注"`
+	}
+	rows := []Row{
+		{"Jason", groupContact{"123", "a@b.c"}, groupContact{"456", "d@e.f"}, "x"},
+		{"Tom", groupContact{"789", ""}, groupContact{"", "g@h.i"}, ""},
+	}
+	c, err := NewCodec[Row](&DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Export(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Import(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+
+	// 手工表格: 合并单元格只在左上角有值
+	f := excelize.NewFile()
+	defer f.Close()
+	for cell, v := range map[string]string{
+		"B1": "本人", "D1": "紧急联系人",
+		"A2": "姓名", "B2": "电话", "C2": "邮箱", "D2": "电话", "E2": "邮箱", "F2": "备注",
+		"A3": "Jason", "B3": "123", "C3": "a@b.c", "D3": "456", "E3": "d@e.f", "F3": "x",
+	} {
+		if err := f.SetCellValue("Sheet1", cell, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, r := range [][2]string{{"B1", "C1"}, {"D1", "E1"}} {
+		if err := f.MergeCell("Sheet1", r[0], r[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = NewCodec[Row](&DefaultOption{SheetName: "Sheet1"}, &Layout{NoTitle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = c.Import(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows[:1]) {
+		t.Errorf("Import() = %+v, want %+v", got, rows[:1])
+	}
+}
+
+func TestCodec_RequiredDefault(t *testing.T) {
+	type Row struct {
+		Name  string `excel:"姓名,required"`
+		Score int    `excel:"分数,default=60"`
+	}
+	data, err := Export([]Row{{"Jason", 0}, {"", 90}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[Row](bytes.NewReader(data), WithCollectErrors())
+	var importErr *ImportError
+	if !errors.As(err, &importErr) || !errors.Is(err, ErrRequired) || importErr.Row != 4 {
+		t.Fatalf("Import() error = %v, want ErrRequired at row 4", err)
+	}
+	if !reflect.DeepEqual(got, []Row{{"Jason", 60}}) {
+		t.Errorf("Import() = %+v", got)
+	}
+}
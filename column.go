@@ -2,74 +2,425 @@ package go_excel
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+// Parser 列值转换器，导出时把字段值转换为单元格的值，导入时把单元格文本转换为字段值
 type Parser interface {
-	Convert() map[string]any
+	Convert(value any) (any, error)
 }
 
 type Column struct {
-	Field       string
+	Field       string // 字段名，嵌套字段为 Address.City 形式的路径
+	FieldIndex  []int  // 字段索引路径
 	FieldType   reflect.Type
 	NaturalName string
-	Index       int // 索引
-	ExportFunc  Parser
-	ImportFunc  Parser
-	Col         string // 列索引
-	IsImage     bool   // 新增图片标识
+	Index       int                 // 索引
+	ExportFunc  Parser              // 导出转换器
+	ImportFunc  Parser              // 导入转换器
+	Col         string              // 列索引
+	IsImage     bool                // 新增图片标识
+	Groups      []string            // 上级分组表头，从外到内
+	Width       float64             // 列宽
+	Format      string              // 数字格式
+	Default     string              // 导入时空单元格的默认值
+	Required    bool                // 导入时不能为空
+	Dict        string              // 字典名称
+	Validation  *Validation         // 数据验证规则
+	Remind      string              // 提示行的内容
+	Example     string              // 模板中示例行的值
+	Aliases     []string            // 导入时可以匹配的其他表头
+	Conditions  []ConditionalFormat // 条件格式
+	Aggregate   string              // 合计行的汇总函数，如 sum
+	Formula     string              // 公式，{row} 为行号，{字段名} 为列名
+	Link        bool                // 超链接列
+	LinkIndex   []int               // 保存链接地址的字段，为 nil 时链接地址为字段的值
 }
 
+// 按数据的类型设置列，切片元素的类型从切片类型取得，元素为接口时按第一个元素的值
 func (e *Excel) getField(data any) error {
-	val, is := refType(data)
-	if !is {
-		return errors.New("model type err")
-	}
-	rt := reflect.TypeOf(val)
-	fieldsMap := make(map[string]*Column)
-	rowsMap := make(map[string]*Column)
-	index := 0
+	rt, ok := modelType(reflect.TypeOf(data))
+	if !ok {
+		val, is := refType(data)
+		if !is {
+			return errors.New("model type err")
+		}
+		rt = reflect.TypeOf(val)
+	}
+	return e.loadFields(rt)
+}
+
+// 数据的结构体类型，可以是结构体、结构体的切片及它们的指针
+func modelType(rt reflect.Type) (reflect.Type, bool) {
+	if rt == nil {
+		return nil, false
+	}
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() == reflect.Slice {
+		rt = rt.Elem()
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+	}
+	return rt, rt.Kind() == reflect.Struct
+}
+
+// 解析后的列，按结构体类型缓存，Excel 和 Codec 共用，只读
+type fieldSet struct {
+	fields  map[string]*Column
+	rows    map[string]*Column
+	headers map[string]*Column
+	rt      reflect.Type
+}
+
+// 结构体类型 / *fieldSet，注册转换器后清空
+var fieldCache sync.Map
+
+// 设置结构体类型的列，每个类型的标签只解析校验一次
+func (e *Excel) loadFields(rt reflect.Type) error {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if v, ok := fieldCache.Load(rt); ok {
+		set := v.(*fieldSet)
+		e.Fields, e.Rows, e.headers, e.ModelRt = set.fields, set.rows, set.headers, set.rt
+		return nil
+	}
+	if err := e.parseFields(rt); err != nil {
+		return err
+	}
+	fieldCache.Store(rt, &fieldSet{fields: e.Fields, rows: e.Rows, headers: e.headers, rt: e.ModelRt})
+	return nil
+}
+
+// 解析结构体类型的 excel 标签
+func (e *Excel) parseFields(rt reflect.Type) error {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return fmt.Errorf("model type err: %s is not a struct", rt)
+	}
+	p := &fieldParser{
+		fields:  make(map[string]*Column),
+		rows:    make(map[string]*Column),
+		headers: make(map[string]*Column),
+		leaves:  make(map[string]*Column),
+		visited: make(map[reflect.Type]bool),
+	}
+	if err := p.parse(rt, nil, "", "", nil); err != nil {
+		return err
+	}
+	// 分组下的表头在不重复时也可以单独匹配，如缺少分组行的文件
+	for leaf, col := range p.leaves {
+		if _, ok := p.headers[leaf]; !ok && col != nil {
+			p.headers[leaf] = col
+		}
+	}
+	if p.index == 0 {
+		return fmt.Errorf("model type err: %s has no excel tagged field", rt)
+	}
+	e.Rows = p.rows
+	e.headers = p.headers
+	e.Fields = p.fields
+	e.ModelRt = rt
+	return nil
+}
+
+// 解析 excel 标签的中间状态
+type fieldParser struct {
+	fields  map[string]*Column
+	rows    map[string]*Column
+	headers map[string]*Column    // 规范化后的分组路径、表头和别名
+	leaves  map[string]*Column    // 分组下规范化后的表头，重复时为 nil
+	visited map[reflect.Type]bool // 当前路径上的结构体类型，用于发现循环引用
+	index   int
+}
+
+// 解析结构体字段，嵌套结构体展开为多列，表头加上 prefix 前缀，groups 为上级分组
+func (p *fieldParser) parse(rt reflect.Type, index []int, path, prefix string, groups []string) error {
+	if p.visited[rt] {
+		return fmt.Errorf("recursive excel field type %s", rt)
+	}
+	p.visited[rt] = true
+	defer delete(p.visited, rt)
+
 	for i := 0; i < rt.NumField(); i++ {
-		tagName := rt.Field(i).Tag.Get("excel")
+		sf := rt.Field(i)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		fieldName := sf.Name
+		if path != "" {
+			fieldName = path + "." + sf.Name
+		}
+		st := sf.Type
+		for st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+
+		tagName := sf.Tag.Get("excel")
 		if tagName == "" {
+			// 匿名结构体的字段提升到上层
+			if sf.Anonymous && isNestedType(st) {
+				if err := p.parse(st, fieldIndex, path, prefix, groups); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
-		tags := strings.Split(tagName, " ")
-		if len(tags) == 0 {
+		if tagName == "-" {
+			continue
+		}
+		tag, err := parseTag(tagName)
+		if err != nil {
+			return fmt.Errorf("excel tag of field %s: %w", fieldName, err)
+		}
+		fieldGroups := groups
+		if v, ok := tag.value("group"); ok {
+			// group=联系方式 或多级的 group=基本信息/联系方式
+			fieldGroups = append(append(make([]string, 0, len(groups)), groups...), strings.Split(v, "/")...)
+		}
+		// 实现 encoding.TextMarshaler 的结构体作为一列，只实现 String 方法的结构体仍按字段展开
+		if _, conv := tag.value("conv"); !conv && isNestedType(st) && !isTextMarshaler(st) {
+			if err := tag.checkNested(); err != nil {
+				return fmt.Errorf("excel tag of field %s: %w", fieldName, err)
+			}
+			// 嵌套结构体默认以 "表头-" 为前缀，带 group 标识时改为以表头分组
+			childPrefix := prefix + tag.Name + "-"
+			if tag.flag("group") {
+				childPrefix = prefix
+				fieldGroups = append(append(make([]string, 0, len(fieldGroups)+1), fieldGroups...), tag.Name)
+			}
+			if v, ok := tag.value("prefix"); ok {
+				childPrefix = prefix + v
+			}
+			if err := p.parse(st, fieldIndex, fieldName, childPrefix, fieldGroups); err != nil {
+				return err
+			}
 			continue
 		}
+
+		header := prefix + tag.Name
+		// 不同分组下可以有相同的表头，如 本人/电话 和 紧急联系人/电话
+		key := headerKey(fieldGroups, header)
+		if _, ok := p.rows[key]; ok {
+			return fmt.Errorf("duplicate excel header %q on field %s", key, fieldName)
+		}
 		filed := new(Column)
-		filed.NaturalName = tags[0]
-		filed.Field = rt.Field(i).Name
-		filed.FieldType = rt.Field(i).Type
-		filed.Index = index
-		fieldsMap[rt.Field(i).Name] = filed
-		rowsMap[tags[0]] = filed
-
-		// 检查是否包含 img 属性
-		for _, tag := range tags {
-			if tag == "img" {
-				filed.IsImage = true
-				break
+		filed.NaturalName = header
+		filed.Field = fieldName
+		filed.FieldIndex = fieldIndex
+		filed.FieldType = sf.Type
+		filed.Index = p.index
+		filed.Groups = fieldGroups
+		if err := filed.applyTag(tag); err != nil {
+			return fmt.Errorf("excel tag of field %s: %w", fieldName, err)
+		}
+		if v, ok := tag.value("link"); ok {
+			if filed.LinkIndex, err = linkIndex(rt, index, v); err != nil {
+				return fmt.Errorf("excel tag of field %s: %w", fieldName, err)
+			}
+		}
+		if v, ok := tag.value("alias"); ok {
+			for _, alias := range strings.Split(v, "|") {
+				filed.Aliases = append(filed.Aliases, prefix+alias)
+			}
+		}
+		p.fields[fieldName] = filed
+		p.rows[key] = filed
+		for _, name := range append([]string{header}, filed.Aliases...) {
+			key := normalizeHeader(headerKey(fieldGroups, name))
+			if other, ok := p.headers[key]; ok && other != filed {
+				return fmt.Errorf("excel header %q on field %s conflicts with %q", name, fieldName, other.headerKey())
+			}
+			p.headers[key] = filed
+			if len(fieldGroups) > 0 {
+				leaf := normalizeHeader(name)
+				_, dup := p.leaves[leaf]
+				p.leaves[leaf] = filed
+				if dup {
+					p.leaves[leaf] = nil
+				}
 			}
 		}
 
-		index++
+		p.index++
 	}
-	e.Rows = rowsMap
-	e.Fields = fieldsMap
-	e.ModelRt = rt
 	return nil
 }
 
-func getFiledMap(tags []string) map[string]struct{} {
-	filedMap := make(map[string]struct{})
-	for _, k := range tags {
-		if _, ok := filedMap[k]; !ok {
-			filedMap[k] = struct{}{}
+// 将标签选项设置到列上
+func (c *Column) applyTag(tag *columnTag) error {
+	c.IsImage = tag.flag("img")
+	if err := c.setConverter(tag); err != nil {
+		return err
+	}
+	// 只能导出为文本的类型，导入非空单元格时报错
+	if !c.IsImage && c.ImportFunc == nil && !isSupportedType(c.FieldType) && !isTextType(c.FieldType) {
+		return fmt.Errorf("unsupported field type %s", c.FieldType)
+	}
+	if tag.flag("group") {
+		return errors.New(`option "group" requires a value`)
+	}
+	if _, ok := tag.value("prefix"); ok {
+		return errors.New(`option "prefix" is only supported on nested struct`)
+	}
+	if v, ok := tag.value("width"); ok {
+		width, err := strconv.ParseFloat(v, 64)
+		if err != nil || width <= 0 || width > 255 {
+			return fmt.Errorf("invalid width %q", v)
+		}
+		c.Width = width
+	}
+	if v, ok := tag.value("format"); ok {
+		if v == "" {
+			return errors.New("empty format")
+		}
+		c.Format = v
+	}
+	if v, ok := tag.value("dict"); ok {
+		if _, has := tag.value("conv"); has {
+			return errors.New(`option "dict" cannot be used with "conv"`)
+		}
+		if _, ok := lookupDict(v); !ok {
+			return fmt.Errorf("unknown dict %q", v)
+		}
+		c.Dict = v
+	}
+	if v, ok := tag.value("formula"); ok {
+		if c.IsImage || c.Dict != "" {
+			return errors.New(`option "formula" cannot be used with "img" or "dict"`)
+		}
+		if c.Formula = strings.TrimPrefix(v, "="); c.Formula == "" {
+			return errors.New("empty formula")
 		}
 	}
-	return filedMap
+	if err := c.setLink(tag); err != nil {
+		return err
+	}
+	if err := c.setValidation(tag); err != nil {
+		return err
+	}
+	if v, ok := tag.value("default"); ok {
+		// 字典列的默认值是名称，导入时再转换
+		if _, err := c.convert(v); err != nil && c.Dict == "" {
+			return fmt.Errorf("invalid default %q: %w", v, err)
+		}
+		c.Default = v
+	}
+	if v, ok := tag.value("example"); ok {
+		if _, err := c.convert(v); err != nil && c.Dict == "" {
+			return fmt.Errorf("invalid example %q: %w", v, err)
+		}
+		c.Example = v
+	}
+	if v, ok := tag.value("cond"); ok {
+		conditions, err := parseConditions(v)
+		if err != nil {
+			return err
+		}
+		c.Conditions = conditions
+	}
+	if v, ok := tag.value("agg"); ok {
+		if err := checkAggregate(v); err != nil {
+			return err
+		}
+		c.Aggregate = v
+	}
+	c.Required = tag.flag("required")
+	c.Remind, _ = tag.value("remind")
+	return nil
+}
+
+// 需要展开为多列的结构体类型，time.Time、sql.NullString 和注册了转换器的类型作为单个值处理
+func isNestedType(rt reflect.Type) bool {
+	if _, ok := lookupTypeConverter(rt); ok {
+		return false
+	}
+	if _, ok := lookupTypeConverter(valueType(rt)); ok {
+		return false
+	}
+	return rt.Kind() == reflect.Struct && !isSupportedType(rt)
+}
+
+// 将单元格文本转换为字段类型的值，设置了导入转换器时使用转换器
+func (c *Column) convert(val string) (any, error) {
+	if c.ImportFunc == nil {
+		return convertStringToType(val, c.FieldType)
+	}
+	v, err := c.ImportFunc.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return reflect.Zero(c.FieldType).Interface(), nil
+	}
+	if !rv.Type().AssignableTo(c.FieldType) {
+		if !rv.Type().ConvertibleTo(c.FieldType) {
+			return nil, fmt.Errorf("converter returned %s, field type is %s", rv.Type(), c.FieldType)
+		}
+		rv = rv.Convert(c.FieldType)
+	}
+	return rv.Interface(), nil
+}
+
+// 导入时读取单元格显示的文本，字符串字段不读取原始值，如日期单元格导入为 2024-01-02 而不是 45293
+func (c *Column) readsText() bool {
+	return c.ImportFunc == nil && c.Dict == "" && valueType(c.FieldType).Kind() == reflect.String
+}
+
+// 按索引路径取字段，路径上的空指针自动分配
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate nil pointer to %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// 按索引顺序返回字段
+func (e *Excel) columns() []*Column {
+	cols := make([]*Column, 0, len(e.Fields))
+	for _, v := range e.Fields {
+		cols = append(cols, v)
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		return cols[i].Index < cols[j].Index
+	})
+	return cols
+}
+
+// 分组路径和表头组成的键，如 本人/电话，没有分组时为表头
+func headerKey(groups []string, header string) string {
+	return strings.Join(append(slices.Clone(groups), header), "/")
+}
+
+func (c *Column) headerKey() string {
+	return headerKey(c.Groups, c.NaturalName)
+}
+
+// 分组表头的层数
+func (e *Excel) groupDepth() int {
+	depth := 0
+	for _, v := range e.Fields {
+		depth = max(depth, len(v.Groups))
+	}
+	return depth
 }
@@ -0,0 +1,201 @@
+package go_excel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ConditionalFormat 列的条件格式，Style 为满足条件时的样式，导出时创建后设置到 Format。
+// 可以通过 WithConditionalFormat 或标签 cond 设置，标签中的多条规则用 | 分隔：
+//
+//	excel:"库存,cond=<10"                  小于 10 时浅红色填充
+//	excel:"库存,cond='<10:red|>=100:green'" 颜色可以是 red、yellow、green 或 #RRGGBB
+//	excel:"金额,cond=databar"              数据条，可以指定颜色 databar:#638EC6
+//	excel:"完成率,cond=colorscale"         红黄绿三色刻度，colorscale:#FFFFFF:#63BE7B 为双色刻度
+//	excel:"评分,cond=iconset:3Arrows"      图标集
+//	excel:"编号,cond=duplicate"            突出显示重复值，unique 为唯一值
+type ConditionalFormat struct {
+	excelize.ConditionalFormatOptions
+	Style *excelize.Style
+}
+
+// WithConditionalFormat 为列添加条件格式，column 为字段名或表头，分组下的表头可以带分组路径，如 本人/电话
+func WithConditionalFormat(column string, rules ...ConditionalFormat) Option {
+	return optionFunc(func(options *Options) {
+		formats := make(map[string][]ConditionalFormat, len(options.Conditions)+1)
+		for k, v := range options.Conditions {
+			formats[k] = v
+		}
+		formats[column] = append(slices.Clip(formats[column]), rules...)
+		options.Conditions = formats
+	})
+}
+
+// 突出显示的颜色，依次为填充色和字体颜色
+var highlightColors = map[string][2]string{
+	"red":    {"#FFC7CE", "#9C0006"},
+	"yellow": {"#FFEB9C", "#9C5700"},
+	"green":  {"#C6EFCE", "#006100"},
+}
+
+var (
+	cellRulePattern = regexp.MustCompile(`^(<=|>=|<>|!=|==|<|>|=)\s*(-?[0-9.]+)$`)
+	colorPattern    = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+)
+
+// 图标集样式
+var iconStyles = []string{
+	"3Arrows", "3ArrowsGray", "3Flags", "3Signs", "3Symbols", "3Symbols2", "3TrafficLights1", "3TrafficLights2",
+	"4Arrows", "4ArrowsGray", "4Rating", "4RedToBlack", "4TrafficLights",
+	"5Arrows", "5ArrowsGray", "5Quarters", "5Rating",
+}
+
+// 解析标签 cond 的规则
+func parseConditions(tag string) ([]ConditionalFormat, error) {
+	items := strings.Split(tag, "|")
+	rules := make([]ConditionalFormat, 0, len(items))
+	for _, item := range items {
+		args := strings.Split(strings.TrimSpace(item), ":")
+		for i := range args {
+			args[i] = strings.TrimSpace(args[i])
+		}
+		rule, err := parseCondition(args[0], args[1:])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseCondition(kind string, args []string) (ConditionalFormat, error) {
+	var rule ConditionalFormat
+	for _, color := range args {
+		if _, ok := highlightColors[color]; !ok && !colorPattern.MatchString(color) && kind != "iconset" {
+			return rule, fmt.Errorf("invalid color %q in condition %q", color, kind)
+		}
+	}
+	arg := func(i int, def string) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+	switch kind {
+	case "databar":
+		if len(args) > 1 {
+			break
+		}
+		rule.Type = "data_bar"
+		rule.Criteria = "="
+		rule.MinType, rule.MaxType = "min", "max"
+		rule.BarColor = arg(0, "#638EC6")
+		return rule, nil
+	case "colorscale":
+		switch len(args) {
+		case 0:
+			rule.Type = "3_color_scale"
+			rule.Criteria = "="
+			rule.MinType, rule.MidType, rule.MaxType = "min", "percentile", "max"
+			rule.MidValue = "50"
+			rule.MinColor, rule.MidColor, rule.MaxColor = "#F8696B", "#FFEB84", "#63BE7B"
+			return rule, nil
+		case 2:
+			rule.Type = "2_color_scale"
+			rule.Criteria = "="
+			rule.MinType, rule.MaxType = "min", "max"
+			rule.MinColor, rule.MaxColor = args[0], args[1]
+			return rule, nil
+		}
+	case "iconset":
+		style := arg(0, "3TrafficLights1")
+		if len(args) > 1 || !slices.Contains(iconStyles, style) {
+			break
+		}
+		rule.Type = "icon_set"
+		rule.IconStyle = style
+		return rule, nil
+	case "duplicate", "unique":
+		if len(args) > 1 {
+			break
+		}
+		rule.Type = kind
+		rule.Criteria = "="
+		rule.Style = highlightStyle(arg(0, "red"))
+		return rule, nil
+	default:
+		m := cellRulePattern.FindStringSubmatch(kind)
+		if m == nil || len(args) > 1 {
+			break
+		}
+		if _, err := strconv.ParseFloat(m[2], 64); err != nil {
+			break
+		}
+		rule.Type = "cell"
+		rule.Criteria = m[1]
+		rule.Value = m[2]
+		rule.Style = highlightStyle(arg(0, "red"))
+		return rule, nil
+	}
+	return rule, fmt.Errorf("invalid condition %q", strings.Join(append([]string{kind}, args...), ":"))
+}
+
+// 突出显示的样式，color 为颜色名称时同时设置字体颜色
+func highlightStyle(color string) *excelize.Style {
+	fill, font := color, ""
+	if v, ok := highlightColors[color]; ok {
+		fill, font = v[0], v[1]
+	}
+	style := &excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{fill}, Pattern: 1}}
+	if font != "" {
+		style.Font = &excelize.Font{Color: font}
+	}
+	return style
+}
+
+// 为 firstRow 到 lastRow 的数据行添加条件格式，没有数据行时不添加
+func (e *Excel) addConditionalFormats(cols []*Column, firstRow, lastRow int) error {
+	for name := range e.Option.Conditions {
+		if !slices.ContainsFunc(cols, func(col *Column) bool {
+			return col.Field == name || col.NaturalName == name || col.headerKey() == name
+		}) {
+			return fmt.Errorf("conditional format on unknown column %q", name)
+		}
+	}
+	if lastRow < firstRow {
+		return nil
+	}
+	for k, col := range cols {
+		rules := slices.Concat(col.Conditions,
+			e.Option.Conditions[col.Field], e.Option.Conditions[col.NaturalName])
+		if key := col.headerKey(); key != col.NaturalName {
+			rules = append(rules, e.Option.Conditions[key]...)
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		opts := make([]excelize.ConditionalFormatOptions, len(rules))
+		for i, rule := range rules {
+			opts[i] = rule.ConditionalFormatOptions
+			if rule.Style == nil {
+				continue
+			}
+			style, err := e.File.NewConditionalStyle(rule.Style)
+			if err != nil {
+				return err
+			}
+			opts[i].Format = &style
+		}
+		colName := e.colName(k)
+		rangeRef := fmt.Sprintf("%s%d:%s%d", colName, firstRow, colName, lastRow)
+		if err := e.File.SetConditionalFormat(e.Option.SheetName, rangeRef, opts); err != nil {
+			return fmt.Errorf("conditional format on %s: %w", col.NaturalName, err)
+		}
+	}
+	return nil
+}
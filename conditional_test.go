@@ -0,0 +1,123 @@
+package go_excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseConditions(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    []string // 各规则的类型
+		wantErr bool
+	}{
+		{"<10", []string{"cell"}, false},
+		{"<10:green|>=100:#FF0000", []string{"cell", "cell"}, false},
+		{"databar", []string{"data_bar"}, false},
+		{"colorscale", []string{"3_color_scale"}, false},
+		{"colorscale:#FFFFFF:#63BE7B", []string{"2_color_scale"}, false},
+		{"iconset:3Arrows", []string{"icon_set"}, false},
+		{"duplicate|unique:yellow", []string{"duplicate", "unique"}, false},
+		{"<abc", nil, true},
+		{"<10:pink", nil, true},
+		{"iconset:9Stars", nil, true},
+		{"colorscale:#FFFFFF", nil, true},
+		{"sparkle", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			rules, err := parseConditions(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(rules) != len(tt.want) {
+				t.Fatalf("parseConditions() = %d rules, want %d", len(rules), len(tt.want))
+			}
+			for i, rule := range rules {
+				if rule.Type != tt.want[i] {
+					t.Errorf("rule %d type = %s, want %s", i, rule.Type, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExcel_ConditionalFormat(t *testing.T) {
+	type Row struct {
+		Name  string  `excel:"名称,cond=duplicate"`
+		Stock int     `excel:"库存,cond='<10:red|databar'"`
+		Rate  float64 `excel:"完成率,cond=colorscale"`
+		Score int     `excel:"评分"`
+	}
+	rows := []Row{{"a", 5, 0.1, 1}, {"b", 20, 0.5, 2}, {"a", 100, 0.9, 3}}
+	data, err := Export(rows, &DefaultOption{SheetName: "rows"},
+		WithConditionalFormat("评分", ConditionalFormat{
+			ConditionalFormatOptions: excelize.ConditionalFormatOptions{Type: "icon_set", IconStyle: "3Arrows"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	formats, err := f.GetConditionalFormats("rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"A3:A5": {"duplicate"},
+		"B3:B5": {"cell", "data_bar"},
+		"C3:C5": {"3_color_scale"},
+		"D3:D5": {"icon_set"},
+	}
+	if len(formats) != len(want) {
+		t.Errorf("GetConditionalFormats() = %v, want %d ranges", formats, len(want))
+	}
+	for ref, types := range want {
+		got := formats[ref]
+		if len(got) != len(types) {
+			t.Errorf("%s has %d rules, want %d", ref, len(got), len(types))
+			continue
+		}
+		for i, typ := range types {
+			if got[i].Type != typ {
+				t.Errorf("%s rule %d type = %s, want %s", ref, i, got[i].Type, typ)
+			}
+		}
+	}
+	if cell := formats["B3:B5"][0]; cell.Criteria != "less than" || cell.Value != "10" || cell.Format == nil {
+		t.Errorf("threshold rule = %+v, want < 10 with format", cell)
+	}
+
+	if _, err := Export(rows, WithConditionalFormat("缺失", ConditionalFormat{})); err == nil {
+		t.Error("Export() want error for unknown column")
+	}
+
+	// 预留的空行不加条件格式，没有数据时不加
+	for n, ref := range map[int]string{3: "A3:A5", 0: ""} {
+		data, err := Export(rows[:n], &DefaultOption{SheetName: "rows"}, WithValidationRows(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		formats, err := f.GetConditionalFormats("rows")
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, ok := formats[ref]
+		if ref == "" {
+			ok = len(formats) == 0
+		}
+		if !ok {
+			t.Errorf("%d rows: GetConditionalFormats() = %v, want %q", n, formats, ref)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package go_excel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// 一天的时长，Excel 中的时长以天为单位
+const day = 24 * time.Hour
+
+func convertStringToType(val string, typ reflect.Type) (any, error) {
+	v, err := convertString(val, typ)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// 将单元格的文本转换为 typ 类型的值，空文本为零值，指针类型为 nil
+func convertString(val string, typ reflect.Type) (reflect.Value, error) {
+	if val == "" {
+		return reflect.Zero(typ), nil
+	}
+	switch typ {
+	case timeType:
+		// 日期单元格的原始值是序列号
+		if serial, err := strconv.ParseFloat(val, 64); err == nil {
+			t, err := excelize.ExcelDateToTime(serial, false)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+			return reflect.ValueOf(t), nil
+		}
+		t, err := cast.ToTimeInDefaultLocationE(val, time.Local)
+		return reflect.ValueOf(t), err
+	case durationType:
+		// 时长单元格的原始值是天数
+		if days, err := strconv.ParseFloat(val, 64); err == nil {
+			return reflect.ValueOf(time.Duration(days * float64(day)).Round(time.Microsecond)), nil
+		}
+		d, err := time.ParseDuration(val)
+		return reflect.ValueOf(d), err
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		v, err := convertString(val, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+	if reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		ptr := reflect.New(typ)
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val)); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+	if inner, ok := nullValueType(typ); ok {
+		v, err := convertString(val, inner)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ)
+		if err := ptr.Interface().(sql.Scanner).Scan(v.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+
+	v := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := cast.ToBoolE(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// 按十进制解析，"010" 为 10
+		n, err := strconv.ParseInt(integerText(val), 10, typ.Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", val, typ)
+		}
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", val)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(integerText(val), 10, typ.Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", val, typ)
+		}
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", val)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := cast.ToFloat64E(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.OverflowFloat(f) {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", val, typ)
+		}
+		v.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", typ)
+	}
+	return v, nil
+}
+
+// 将字段值转换为 excelize 可以直接写入的值，nil 表示空单元格
+func toCellValue(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case timeType:
+		return v.Interface(), nil
+	case durationType:
+		return float64(v.Int()) / float64(day), nil
+	}
+
+	value := v.Interface()
+	if v.CanAddr() {
+		// 兼容指针接收者实现的接口
+		value = v.Addr().Interface()
+	}
+	switch x := value.(type) {
+	case driver.Valuer:
+		return x.Value()
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	case fmt.Stringer:
+		return x.String(), nil
+	}
+
+	// 基础类型的命名类型转换为基础类型，按数字写入
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32:
+		// 避免 float32 转 float64 后出现多余的小数位
+		return strconv.ParseFloat(strconv.FormatFloat(v.Float(), 'g', -1, 32), 64)
+	case reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	}
+	return v.Interface(), nil
+}
+
+// 去掉整数文本的空白和小数部分的 0，如单元格原始值 25.0 为 25
+func integerText(val string) string {
+	val = strings.TrimSpace(val)
+	if intPart, frac, ok := strings.Cut(val, "."); ok && strings.Trim(frac, "0") == "" {
+		return intPart
+	}
+	return val
+}
+
+// sql.NullString、sql.Null[T] 等可空类型，返回其中值的类型
+func nullValueType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 || !reflect.PointerTo(typ).Implements(scannerType) {
+		return nil, false
+	}
+	if valid := typ.Field(1); valid.Name != "Valid" || valid.Type.Kind() != reflect.Bool {
+		return nil, false
+	}
+	return typ.Field(0).Type, true
+}
+
+// 去掉指针和可空类型后的值类型
+func valueType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if inner, ok := nullValueType(typ); ok {
+		return valueType(inner)
+	}
+	return typ
+}
+
+// 导出时能够转换为文本的类型，实现 encoding.TextMarshaler 或 fmt.Stringer，值或指针接收者均可
+func isTextType(typ reflect.Type) bool {
+	typ = valueType(typ)
+	ptr := reflect.PointerTo(typ)
+	return ptr.Implements(textMarshalerType) || ptr.Implements(stringerType)
+}
+
+// 实现 encoding.TextMarshaler 的类型，值或指针接收者均可
+func isTextMarshaler(typ reflect.Type) bool {
+	return reflect.PointerTo(valueType(typ)).Implements(textMarshalerType)
+}
+
+// 导入时能够从单元格文本转换的类型
+func isSupportedType(typ reflect.Type) bool {
+	typ = valueType(typ)
+	if typ == timeType || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package go_excel
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type convStatus int
+
+type convCode string
+
+// convLevel 同时实现 TextMarshaler 和 TextUnmarshaler
+type convLevel int
+
+func (l convLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *convLevel) UnmarshalText(text []byte) error {
+	if strings.Trim(string(text), "*") != "" {
+		return errors.New("invalid level")
+	}
+	*l = convLevel(len(text))
+	return nil
+}
+
+// convPoint 只实现 TextMarshaler 的结构体，导出为一列
+type convPoint struct {
+	X, Y int
+}
+
+func (p *convPoint) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "(%d,%d)", p.X, p.Y), nil
+}
+
+// convSize 只实现 Stringer 的结构体，仍按字段展开
+type convSize struct {
+	W int `excel:"宽"`
+	H int `excel:"高"`
+}
+
+func (s convSize) String() string {
+	return fmt.Sprintf("%dx%d", s.W, s.H)
+}
+
+func Test_convertStringToType(t *testing.T) {
+	one := 1
+	tests := []struct {
+		name    string
+		val     string
+		typ     reflect.Type
+		want    any
+		wantErr bool
+	}{
+		{"int8", "12", reflect.TypeOf(int8(0)), int8(12), false},
+		{"int8 overflow", "300", reflect.TypeOf(int8(0)), nil, true},
+		{"int32", "-5", reflect.TypeOf(int32(0)), int32(-5), false},
+		{"uint", "7", reflect.TypeOf(uint(0)), uint(7), false},
+		{"uint negative", "-7", reflect.TypeOf(uint(0)), nil, true},
+		{"uint16", "65535", reflect.TypeOf(uint16(0)), uint16(65535), false},
+		{"float32", "1.5", reflect.TypeOf(float32(0)), float32(1.5), false},
+		{"int bad", "abc", reflect.TypeOf(0), nil, true},
+		{"int leading zero", "010", reflect.TypeOf(0), 10, false},
+		{"int leading zero 8", "08", reflect.TypeOf(0), 8, false},
+		{"int raw decimal", "25.0", reflect.TypeOf(0), 25, false},
+		{"int fraction", "2.5", reflect.TypeOf(0), nil, true},
+		{"int hex", "0x10", reflect.TypeOf(0), nil, true},
+		{"uint leading zero", "09", reflect.TypeOf(uint(0)), uint(9), false},
+		{"named int", "3", reflect.TypeOf(convStatus(0)), convStatus(3), false},
+		{"named string", "A1", reflect.TypeOf(convCode("")), convCode("A1"), false},
+		{"pointer", "1", reflect.TypeOf(&one), &one, false},
+		{"pointer empty", "", reflect.TypeOf(&one), (*int)(nil), false},
+		{"duration text", "1h30m", reflect.TypeOf(time.Duration(0)), 90 * time.Minute, false},
+		{"duration days", "0.0625", reflect.TypeOf(time.Duration(0)), 90 * time.Minute, false},
+		{"null string", "abc", reflect.TypeOf(sql.NullString{}), sql.NullString{String: "abc", Valid: true}, false},
+		{"null string empty", "", reflect.TypeOf(sql.NullString{}), sql.NullString{}, false},
+		{"null int64", "42", reflect.TypeOf(sql.NullInt64{}), sql.NullInt64{Int64: 42, Valid: true}, false},
+		{"null float64", "1.5", reflect.TypeOf(sql.NullFloat64{}), sql.NullFloat64{Float64: 1.5, Valid: true}, false},
+		{"null bool", "true", reflect.TypeOf(sql.NullBool{}), sql.NullBool{Bool: true, Valid: true}, false},
+		{"null generic", "8", reflect.TypeOf(sql.Null[int32]{}), sql.Null[int32]{V: 8, Valid: true}, false},
+		{"text unmarshaler", "***", reflect.TypeOf(convLevel(0)), convLevel(3), false},
+		{"text unmarshaler bad", "**x", reflect.TypeOf(convLevel(0)), nil, true},
+		{"unsupported", "a", reflect.TypeOf([]string{}), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertStringToType(tt.val, tt.typ)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertStringToType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertStringToType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodec_AllTypes(t *testing.T) {
+	type Row struct {
+		Int8     int8            `excel:"int8"`
+		Uint32   uint32          `excel:"uint32"`
+		Float32  float32         `excel:"float32"`
+		Status   convStatus      `excel:"status"`
+		Code     convCode        `excel:"code"`
+		Ptr      *int            `excel:"ptr"`
+		Duration time.Duration   `excel:"duration"`
+		Name     sql.NullString  `excel:"name"`
+		Count    sql.NullInt64   `excel:"count"`
+		Day      sql.NullTime    `excel:"day"`
+		Level    convLevel       `excel:"level"`
+		Score    sql.Null[int16] `excel:"score"`
+	}
+	n := 5
+	rows := []Row{
+		{1, 2, 0.1, 3, "A", &n, 90*time.Minute + 15*time.Second,
+			sql.NullString{String: "x", Valid: true}, sql.NullInt64{Int64: 0, Valid: true},
+			sql.NullTime{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local), Valid: true}, 2,
+			sql.Null[int16]{V: 9, Valid: true}},
+		// 全部为空的行导入时跳过，保留一个非空值
+		{Int8: -1},
+	}
+	data, err := Export(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[Row](bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+
+	type Bad struct {
+		Tags []string `excel:"标签"`
+	}
+	if _, err := NewCodec[Bad](); err == nil {
+		t.Error("NewCodec() want error for unsupported type")
+	}
+}
+
+func TestCodec_TextMarshalerStruct(t *testing.T) {
+	type Row struct {
+		Name  string     `excel:"名称"`
+		Start convPoint  `excel:"起点"`
+		End   *convPoint `excel:"终点"`
+	}
+	data, err := Export([]Row{{"a", convPoint{1, 2}, &convPoint{3, 4}}, {"b", convPoint{}, nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"名称", "起点", "终点"}, {"a", "(1,2)", "(3,4)"}, {"b"}}
+	if !reflect.DeepEqual(rows[1:], want) {
+		t.Errorf("rows = %q, want %q", rows[1:], want)
+	}
+	// 不能从文本转换，导入非空单元格时报错
+	if _, err := Import[Row](bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "unsupported type") {
+		t.Errorf("Import() error = %v, want unsupported type", err)
+	}
+
+	type Box struct {
+		Name string   `excel:"名称"`
+		Size convSize `excel:"尺寸"`
+	}
+	boxes := []Box{{"a", convSize{1, 2}}}
+	data, err = Export(boxes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[Box](bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, boxes) {
+		t.Errorf("Import() = %+v, want %+v", got, boxes)
+	}
+}
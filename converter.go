@@ -0,0 +1,180 @@
+package go_excel
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ParserFunc 将函数适配为 Parser
+type ParserFunc func(value any) (any, error)
+
+func (f ParserFunc) Convert(value any) (any, error) {
+	return f(value)
+}
+
+// 一对导出、导入转换器，typ 为适用的字段类型，nil 表示不限
+type converter struct {
+	typ        reflect.Type
+	exportFunc Parser
+	importFunc Parser
+}
+
+var (
+	convertersMu   sync.RWMutex
+	converters     = make(map[string]converter)       // 通过标签 conv=name 使用的转换器
+	typeConverters = make(map[reflect.Type]converter) // 按字段类型自动使用的转换器
+)
+
+// RegisterParser 注册命名转换器，exportFunc 把字段值转换为单元格的值，
+// importFunc 把单元格文本转换为字段值，任一个为 nil 时该方向按默认规则处理
+func RegisterParser(name string, exportFunc, importFunc Parser) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[name] = converter{exportFunc: exportFunc, importFunc: importFunc}
+	fieldCache.Clear()
+}
+
+// RegisterConverter 注册类型安全的命名转换器，字段使用 excel:"状态,conv=status" 引用
+func RegisterConverter[T any](name string, exportFunc func(T) (any, error), importFunc func(string) (T, error)) {
+	c := newConverter(exportFunc, importFunc)
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[name] = c
+	fieldCache.Clear()
+}
+
+// RegisterTypeConverter 注册按类型使用的转换器，所有 T 类型的字段默认使用
+func RegisterTypeConverter[T any](exportFunc func(T) (any, error), importFunc func(string) (T, error)) {
+	c := newConverter(exportFunc, importFunc)
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	typeConverters[c.typ] = c
+	fieldCache.Clear()
+}
+
+func newConverter[T any](exportFunc func(T) (any, error), importFunc func(string) (T, error)) converter {
+	c := converter{typ: reflect.TypeOf((*T)(nil)).Elem()}
+	if exportFunc != nil {
+		c.exportFunc = ParserFunc(func(value any) (any, error) {
+			v, ok := value.(T)
+			if !ok {
+				return nil, fmt.Errorf("converter expects %s, got %T", c.typ, value)
+			}
+			return exportFunc(v)
+		})
+	}
+	if importFunc != nil {
+		c.importFunc = ParserFunc(func(value any) (any, error) {
+			return importFunc(fmt.Sprint(value))
+		})
+	}
+	return c
+}
+
+func lookupConverter(name string) (converter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	c, ok := converters[name]
+	return c, ok
+}
+
+func lookupTypeConverter(typ reflect.Type) (converter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	c, ok := typeConverters[typ]
+	return c, ok
+}
+
+// 为列设置转换器，标签指定的优先于按类型注册的。
+// 指针和可空类型的字段使用其中值类型的转换器，如 *Money 使用 Money 的转换器
+func (c *Column) setConverter(tag *columnTag) error {
+	typ := valueType(c.FieldType)
+	conv, ok := lookupTypeConverter(c.FieldType)
+	if !ok {
+		conv, ok = lookupTypeConverter(typ)
+	}
+	if name, has := tag.value("conv"); has {
+		conv, ok = lookupConverter(name)
+		if !ok {
+			return fmt.Errorf("unknown converter %q", name)
+		}
+		if conv.typ != nil && conv.typ != c.FieldType && conv.typ != typ {
+			return fmt.Errorf("converter %q expects %s, field type is %s", name, conv.typ, c.FieldType)
+		}
+	}
+	if !ok {
+		return nil
+	}
+	c.ExportFunc = conv.exportFunc
+	c.ImportFunc = conv.importFunc
+	if conv.typ != nil && conv.typ != c.FieldType {
+		c.ExportFunc, c.ImportFunc = conv.wrap(c.FieldType)
+	}
+	return nil
+}
+
+// 将值类型的转换器包装为 fieldType 字段的转换器，导出时取出指针和可空类型中的值，
+// 空指针和无效值导出为空单元格，导入时将结果包装为字段类型
+func (c converter) wrap(fieldType reflect.Type) (exportFunc, importFunc Parser) {
+	if c.exportFunc != nil {
+		exportFunc = ParserFunc(func(value any) (any, error) {
+			v, ok := innerValue(reflect.ValueOf(value))
+			if !ok {
+				return nil, nil
+			}
+			return c.exportFunc.Convert(v.Interface())
+		})
+	}
+	if c.importFunc != nil {
+		importFunc = ParserFunc(func(value any) (any, error) {
+			v, err := c.importFunc.Convert(value)
+			if err != nil {
+				return nil, err
+			}
+			return wrapValue(reflect.ValueOf(v), fieldType).Interface(), nil
+		})
+	}
+	return exportFunc, importFunc
+}
+
+// 取出指针和可空类型中的值，空指针和无效值返回 false
+func innerValue(v reflect.Value) (reflect.Value, bool) {
+	for v.IsValid() {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+			continue
+		}
+		if _, ok := nullValueType(v.Type()); ok {
+			if !v.Field(1).Bool() {
+				return v, false
+			}
+			v = v.Field(0)
+			continue
+		}
+		return v, true
+	}
+	return v, false
+}
+
+// 将值包装为指针或有效的可空类型
+func wrapValue(v reflect.Value, typ reflect.Type) reflect.Value {
+	if v.Type() == typ {
+		return v
+	}
+	if typ.Kind() == reflect.Ptr {
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(wrapValue(v, typ.Elem()))
+		return ptr
+	}
+	if inner, ok := nullValueType(typ); ok {
+		null := reflect.New(typ).Elem()
+		null.Field(0).Set(wrapValue(v, inner))
+		null.Field(1).SetBool(true)
+		return null
+	}
+	return v
+}
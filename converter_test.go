@@ -0,0 +1,136 @@
+package go_excel
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type convMoney struct {
+	Cents int64
+}
+
+func init() {
+	RegisterConverter("yesno", func(v bool) (any, error) {
+		if v {
+			return "是", nil
+		}
+		return "否", nil
+	}, func(s string) (bool, error) {
+		switch s {
+		case "是":
+			return true, nil
+		case "否":
+			return false, nil
+		}
+		return false, fmt.Errorf("invalid yes/no %q", s)
+	})
+	RegisterParser("upper", ParserFunc(func(v any) (any, error) {
+		return strings.ToUpper(fmt.Sprint(v)), nil
+	}), nil)
+	RegisterTypeConverter(func(m convMoney) (any, error) {
+		return fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100), nil
+	}, func(s string) (convMoney, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return convMoney{}, err
+		}
+		return convMoney{Cents: int64(f*100 + 0.5)}, nil
+	})
+}
+
+func TestCodec_Converter(t *testing.T) {
+	type Row struct {
+		Name   string    `excel:"名称,conv=upper"`
+		Active bool      `excel:"启用,conv=yesno"`
+		Price  convMoney `excel:"价格"`
+	}
+	rows := []Row{{"APPLE", true, convMoney{1250}}, {"PEAR", false, convMoney{99}}}
+	data, err := Export(rows, &DefaultOption{SheetName: "goods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[Row](bytes.NewReader(data), &DefaultOption{SheetName: "goods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+
+	t.Run("import error", func(t *testing.T) {
+		type Text struct {
+			Active string `excel:"启用"`
+		}
+		data, err := Export([]Text{{"可能"}}, &DefaultOption{SheetName: "goods"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Import[Row](bytes.NewReader(data), &DefaultOption{SheetName: "goods"})
+		var importErr *ImportError
+		if !errors.As(err, &importErr) || importErr.Header != "启用" {
+			t.Errorf("Import() error = %v, want ImportError on 启用", err)
+		}
+	})
+
+	t.Run("pointer field", func(t *testing.T) {
+		// 指针和可空类型的字段使用值类型的转换器，空值导出为空单元格
+		type Ptr struct {
+			Name   string              `excel:"名称"`
+			Active *bool               `excel:"启用,conv=yesno"`
+			Price  *convMoney          `excel:"价格"`
+			Paid   sql.Null[convMoney] `excel:"已付"`
+		}
+		yes := true
+		rows := []Ptr{
+			{"apple", &yes, &convMoney{1250}, sql.Null[convMoney]{V: convMoney{99}, Valid: true}},
+			{"pear", nil, nil, sql.Null[convMoney]{}},
+		}
+		data, err := Export(rows, &DefaultOption{SheetName: "goods"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		cells, err := f.GetRows("goods")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{{"apple", "是", "12.50", "0.99"}, {"pear"}}
+		if !reflect.DeepEqual(cells[2:], want) {
+			t.Errorf("rows = %q, want %q", cells[2:], want)
+		}
+		got, err := Import[Ptr](bytes.NewReader(data), &DefaultOption{SheetName: "goods"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("Import() = %+v, want %+v", got, rows)
+		}
+	})
+
+	t.Run("bad tag", func(t *testing.T) {
+		type Unknown struct {
+			Name string `excel:"名称,conv=missing"`
+		}
+		type Mismatch struct {
+			Name string `excel:"名称,conv=yesno"`
+		}
+		if _, err := NewCodec[Unknown](); err == nil {
+			t.Error("NewCodec() want error for unknown converter")
+		}
+		if _, err := NewCodec[Mismatch](); err == nil {
+			t.Error("NewCodec() want error for converter type mismatch")
+		}
+	})
+}
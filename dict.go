@@ -0,0 +1,247 @@
+package go_excel
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DictItem 字典项，Value 为字段中保存的编码，Label 为表格中显示的名称
+type DictItem struct {
+	Value any
+	Label string
+}
+
+// DictProvider 字典数据源，每次导入导出时读取一次，可以从数据库等加载
+type DictProvider interface {
+	Items() ([]DictItem, error)
+}
+
+// DictProviderFunc 将函数适配为 DictProvider
+type DictProviderFunc func() ([]DictItem, error)
+
+func (f DictProviderFunc) Items() ([]DictItem, error) {
+	return f()
+}
+
+// DictError 字典中找不到导入的名称
+type DictError struct {
+	Dict  string // 字典名称
+	Label string // 表格中的名称
+}
+
+func (e *DictError) Error() string {
+	return fmt.Sprintf("dict %s has no item labeled %q", e.Dict, e.Label)
+}
+
+// 下拉列表直接写在数据验证中的最大长度，超过时改为引用隐藏工作表中的区域
+const maxDropListLength = 255
+
+// 保存下拉列表选项的隐藏工作表
+const dictSheetName = "_dict"
+
+var (
+	dictsMu sync.RWMutex
+	dicts   = make(map[string]DictProvider)
+)
+
+// RegisterDict 注册固定的字典，字段使用 excel:"状态,dict=status" 引用，下拉列表按 items 的顺序
+func RegisterDict(name string, items ...DictItem) {
+	RegisterDictProvider(name, DictProviderFunc(func() ([]DictItem, error) {
+		return items, nil
+	}))
+}
+
+// RegisterDictMap 以编码到名称的映射注册字典，下拉列表按编码排序
+func RegisterDictMap[K cmp.Ordered](name string, m map[K]string) {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	items := make([]DictItem, len(keys))
+	for i, k := range keys {
+		items[i] = DictItem{Value: k, Label: m[k]}
+	}
+	RegisterDict(name, items...)
+}
+
+// RegisterDictProvider 注册动态字典
+func RegisterDictProvider(name string, provider DictProvider) {
+	dictsMu.Lock()
+	defer dictsMu.Unlock()
+	dicts[name] = provider
+}
+
+func lookupDict(name string) (DictProvider, bool) {
+	dictsMu.RLock()
+	defer dictsMu.RUnlock()
+	p, ok := dicts[name]
+	return p, ok
+}
+
+// 一次导入导出中使用的字典，编码已转换为字段类型
+type dictTable struct {
+	name   string
+	labels []string
+	byCode map[any]string
+	byName map[string]any
+}
+
+// 读取列的字典，同一次导入导出中只读取一次
+func (e *Excel) dict(col *Column) (*dictTable, error) {
+	typ := col.FieldType
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	key := col.Dict + "/" + typ.String()
+	if d, ok := e.dicts[key]; ok {
+		return d, nil
+	}
+	provider, ok := lookupDict(col.Dict)
+	if !ok {
+		return nil, fmt.Errorf("unknown dict %q", col.Dict)
+	}
+	items, err := provider.Items()
+	if err != nil {
+		return nil, fmt.Errorf("load dict %s: %w", col.Dict, err)
+	}
+	d := &dictTable{
+		name:   col.Dict,
+		labels: make([]string, 0, len(items)),
+		byCode: make(map[any]string, len(items)),
+		byName: make(map[string]any, len(items)),
+	}
+	for _, item := range items {
+		code, err := dictCode(item.Value, typ)
+		if err != nil {
+			return nil, fmt.Errorf("dict %s: %w", col.Dict, err)
+		}
+		d.labels = append(d.labels, item.Label)
+		d.byCode[code] = item.Label
+		d.byName[item.Label] = code
+	}
+	if e.dicts == nil {
+		e.dicts = make(map[string]*dictTable)
+	}
+	e.dicts[key] = d
+	return d, nil
+}
+
+// 将字典项的编码转换为字段类型，便于与字段值比较
+func dictCode(v any, typ reflect.Type) (any, error) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		return nil, errors.New("nil dict value")
+	case rv.Type() == typ:
+		return v, nil
+	case rv.Kind() == reflect.String:
+		return convertStringToType(rv.String(), typ)
+	case typ.Kind() == reflect.String:
+		// 避免整数按字符转换为字符串
+		return reflect.ValueOf(fmt.Sprint(v)).Convert(typ).Interface(), nil
+	case rv.Type().ConvertibleTo(typ):
+		return rv.Convert(typ).Interface(), nil
+	}
+	return nil, fmt.Errorf("value %v cannot convert to %s", v, typ)
+}
+
+// 导出时将编码转换为名称，字典中没有的零值写入空单元格，其他编码按原值写入
+func (e *Excel) dictLabel(col *Column, rfval reflect.Value) (any, error) {
+	d, err := e.dict(col)
+	if err != nil {
+		return nil, err
+	}
+	for rfval.Kind() == reflect.Ptr {
+		if rfval.IsNil() {
+			return nil, nil
+		}
+		rfval = rfval.Elem()
+	}
+	if label, ok := d.byCode[rfval.Interface()]; ok {
+		return label, nil
+	}
+	if rfval.IsZero() {
+		return nil, nil
+	}
+	return toCellValue(rfval)
+}
+
+// 导入时将名称转换为编码
+func (e *Excel) dictValue(col *Column, val string) (any, error) {
+	d, err := e.dict(col)
+	if err != nil {
+		return nil, err
+	}
+	code, ok := d.byName[val]
+	if !ok {
+		return nil, &DictError{Dict: d.name, Label: val}
+	}
+	if col.FieldType.Kind() == reflect.Ptr {
+		ptr := reflect.New(col.FieldType.Elem())
+		ptr.Elem().Set(reflect.ValueOf(code))
+		return ptr.Interface(), nil
+	}
+	return code, nil
+}
+
+// 为字典列设置下拉列表，字典为空时返回 false
+func (e *Excel) setDictDropList(col *Column, dv *excelize.DataValidation) (bool, error) {
+	d, err := e.dict(col)
+	if err != nil || len(d.labels) == 0 {
+		return false, err
+	}
+	if len(strings.Join(d.labels, ",")) <= maxDropListLength {
+		return true, dv.SetDropList(d.labels)
+	}
+	ref, err := e.dictRange(d)
+	if err != nil {
+		return false, err
+	}
+	dv.SetSqrefDropList(ref)
+	return true, nil
+}
+
+// 将选项写入隐藏工作表的一列，第一行为字典名称，返回选项所在区域
+func (e *Excel) dictRange(d *dictTable) (string, error) {
+	if index, _ := e.File.GetSheetIndex(dictSheetName); index == -1 {
+		if _, err := e.File.NewSheet(dictSheetName); err != nil {
+			return "", err
+		}
+		if err := e.File.SetSheetVisible(dictSheetName, false); err != nil {
+			return "", err
+		}
+	}
+	names, err := e.File.GetRows(dictSheetName)
+	if err != nil {
+		return "", err
+	}
+	col := 1
+	if len(names) > 0 {
+		for col = 1; col <= len(names[0]); col++ {
+			if names[0][col-1] == d.name {
+				break
+			}
+		}
+	}
+	colName, err := excelize.ColumnNumberToName(col)
+	if err != nil {
+		return "", err
+	}
+	values := make([]any, 0, len(d.labels)+1)
+	values = append(values, d.name)
+	for _, label := range d.labels {
+		values = append(values, label)
+	}
+	if err := e.File.SetSheetCol(dictSheetName, colName+"1", &values); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("'%s'!$%s$2:$%s$%d", dictSheetName, colName, colName, len(d.labels)+1), nil
+}
@@ -0,0 +1,162 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func init() {
+	RegisterDictMap("status", map[int]string{1: "启用", 2: "停用", 3: "删除"})
+	RegisterDict("level", DictItem{"low", "低"}, DictItem{"high", "高"})
+	RegisterDictProvider("city", DictProviderFunc(func() ([]DictItem, error) {
+		items := make([]DictItem, 100)
+		for i := range items {
+			items[i] = DictItem{i, fmt.Sprintf("城市%03d", i)}
+		}
+		return items, nil
+	}))
+}
+
+type dictUser struct {
+	Name   string  `excel:"姓名"`
+	Status int     `excel:"状态,dict=status"`
+	Level  *string `excel:"等级,dict=level"`
+}
+
+func TestDict_RoundTrip(t *testing.T) {
+	high := "high"
+	users := []dictUser{{"Jason", 1, &high}, {"Jackson", 3, nil}}
+	data, err := Export(users, &DefaultOption{SheetName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for cell, want := range map[string]string{"B3": "启用", "C3": "高", "B4": "删除", "C4": ""} {
+		if got, _ := f.GetCellValue("users", cell); got != want {
+			t.Errorf("cell %s = %q, want %q", cell, got, want)
+		}
+	}
+	dvs, err := f.GetDataValidations("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := make(map[string]string)
+	for _, dv := range dvs {
+		lists[dv.Sqref] = dv.Formula1
+	}
+	if got := lists["B3:B4"]; !strings.Contains(got, "启用,停用,删除") {
+		t.Errorf("drop list on B3:B4 = %q, want 启用,停用,删除", got)
+	}
+	if got := lists["C3:C4"]; !strings.Contains(got, "低,高") {
+		t.Errorf("drop list on C3:C4 = %q, want 低,高", got)
+	}
+
+	got, err := Import[dictUser](bytes.NewReader(data), &DefaultOption{SheetName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, users) {
+		t.Errorf("Import() = %+v, want %+v", got, users)
+	}
+}
+
+func TestDict_ZeroCode(t *testing.T) {
+	// 字典中没有的零值导出为空单元格，可以再导入
+	users := []dictUser{{"Jason", 0, nil}, {"Jackson", 2, nil}}
+	data, err := Export(users, &DefaultOption{SheetName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got, _ := f.GetCellValue("users", "B3"); got != "" {
+		t.Errorf("cell B3 = %q, want empty", got)
+	}
+	got, err := Import[dictUser](bytes.NewReader(data), &DefaultOption{SheetName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, users) {
+		t.Errorf("Import() = %+v, want %+v", got, users)
+	}
+}
+
+func TestDict_UnknownLabel(t *testing.T) {
+	type Text struct {
+		Name   string `excel:"姓名"`
+		Status string `excel:"状态"`
+	}
+	data, err := Export([]Text{{"Jason", "冻结"}}, &DefaultOption{SheetName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Import[dictUser](bytes.NewReader(data), &DefaultOption{SheetName: "users"})
+	var dictErr *DictError
+	if !errors.As(err, &dictErr) || dictErr.Dict != "status" || dictErr.Label != "冻结" {
+		t.Fatalf("Import() error = %v, want DictError", err)
+	}
+	var importErr *ImportError
+	if !errors.As(err, &importErr) || importErr.Header != "状态" {
+		t.Errorf("Import() error = %v, want ImportError on 状态", err)
+	}
+}
+
+func TestDict_LongList(t *testing.T) {
+	type Row struct {
+		City int `excel:"城市,dict=city"`
+	}
+	data, err := Export([]Row{{5}, {42}}, &DefaultOption{SheetName: "rows"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if visible, err := f.GetSheetVisible(dictSheetName); err != nil || visible {
+		t.Errorf("sheet %s visible = %v, %v, want hidden", dictSheetName, visible, err)
+	}
+	dvs, err := f.GetDataValidations("rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dvs) != 1 || !strings.Contains(dvs[0].Formula1, "_dict") {
+		t.Fatalf("GetDataValidations() = %+v, want reference to %s", dvs, dictSheetName)
+	}
+	got, err := Import[Row](bytes.NewReader(data), &DefaultOption{SheetName: "rows"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []Row{{5}, {42}}) {
+		t.Errorf("Import() = %+v", got)
+	}
+}
+
+func TestDict_BadTag(t *testing.T) {
+	type Unknown struct {
+		Status int `excel:"状态,dict=missing"`
+	}
+	type WithConv struct {
+		Active bool `excel:"启用,dict=status,conv=yesno"`
+	}
+	if _, err := NewCodec[Unknown](); err == nil {
+		t.Error("NewCodec() want error for unknown dict")
+	}
+	if _, err := NewCodec[WithConv](); err == nil {
+		t.Error("NewCodec() want error for dict with conv")
+	}
+}
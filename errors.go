@@ -0,0 +1,113 @@
+package go_excel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRequired 必填列的单元格为空
+var ErrRequired = errors.New("required value is empty")
+
+// ImportError 导入时单元格级别的错误
+type ImportError struct {
+	Sheet  string // 表名
+	Row    int    // 行号
+	Col    string // 列索引
+	Header string // 表头名称
+	Value  string // 原始值
+	Err    error  // 错误原因
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("sheet %q row %d col %s(%s) value %q: %v",
+		e.Sheet, e.Row, e.Col, e.Header, e.Value, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportErrors 汇总模式下收集到的全部导入错误
+type ImportErrors []*ImportError
+
+func (es ImportErrors) Error() string {
+	if len(es) == 1 {
+		return es[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d import errors:", len(es))
+	for _, e := range es {
+		b.WriteString("\n\t")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+func (es ImportErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// SheetError 多工作表导入时单个工作表的错误
+type SheetError struct {
+	Sheet string // 表名，未匹配到工作表时为匹配规则的描述
+	Err   error
+}
+
+func (e *SheetError) Error() string {
+	return fmt.Sprintf("sheet %q: %v", e.Sheet, e.Err)
+}
+
+func (e *SheetError) Unwrap() error {
+	return e.Err
+}
+
+// SheetErrors 多工作表导入时各工作表的错误
+type SheetErrors []*SheetError
+
+func (es SheetErrors) Error() string {
+	if len(es) == 1 {
+		return es[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d sheet errors:", len(es))
+	for _, e := range es {
+		b.WriteString("\n\t")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+func (es SheetErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// HeaderError 严格模式下表头与结构体不一致
+type HeaderError struct {
+	Sheet     string   // 表名
+	Missing   []string // 缺少的必填列
+	Unknown   []string // 结构体中没有的列
+	Duplicate []string // 重复的列
+}
+
+func (e *HeaderError) Error() string {
+	parts := make([]string, 0, 3)
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing %q", e.Missing))
+	}
+	if len(e.Unknown) > 0 {
+		parts = append(parts, fmt.Sprintf("unknown %q", e.Unknown))
+	}
+	if len(e.Duplicate) > 0 {
+		parts = append(parts, fmt.Sprintf("duplicate %q", e.Duplicate))
+	}
+	return fmt.Sprintf("sheet %q header mismatch: %s", e.Sheet, strings.Join(parts, ", "))
+}
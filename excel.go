@@ -1,18 +1,16 @@
 package go_excel
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
-	"strconv"
 	"strings"
 	"text/template"
-	"time"
 
-	"github.com/spf13/cast"
 	"github.com/xuri/excelize/v2"
 
 	_ "image/gif"
@@ -20,23 +18,46 @@ import (
 	_ "image/png"
 )
 
+// ErrStop 由逐行导入的回调返回，提前结束导入且不视为错误
+var ErrStop = errors.New("stop import")
+
 type Options struct {
-	SheetName    string // 表名
-	Title        string // 标题
-	ShowRemind   bool   // 显示提示
-	DefaultStyle bool   // 自定义样式
-	SwNum        int64  // 流式写入
+	SheetName      string                         // 表名
+	Title          string                         // 标题
+	ShowRemind     bool                           // 表头下方显示提示行
+	DefaultStyle   bool                           // Deprecated: 未使用，样式由 Theme 设置
+	SwNum          int64                          // 流式写入
+	CollectErrors  bool                           // 汇总导入错误
+	AutoWidth      *AutoWidthOption               // 自动列宽
+	DateFormat     string                         // 日期列的默认格式
+	ValidationRows int                            // 数据下方带数据验证的空行数
+	StrictHeader   bool                           // 导入时表头必须与结构体一致
+	Layout         *Layout                        // 工作表布局
+	Conditions     map[string][]ConditionalFormat // 按字段名或表头设置的条件格式
+	RowStyler      RowStyleFunc                   // 按行设置样式
+	Theme          *Theme                         // 样式主题
+	TableTotals    bool                           // 使用表格内置的合计行
+	SkipFormulas   bool                           // 导入时跳过公式列
+
+	rowStyleType reflect.Type // StyleRow 的数据类型
 }
 
 type Excel struct {
 	Fields   map[string]*Column // 字段名称 / 字段
-	Rows     map[string]*Column // 字段表格显示名/ 字段
+	Rows     map[string]*Column // 分组路径和表头，如 本人/电话 / 字段
 	Option   Options
 	ModelRt  reflect.Type
-	RowStyle excelize.Style
+	RowStyle excelize.Style // 数据行的样式
 	File     *excelize.File
 	Sw       *excelize.StreamWriter
 	Data     any
+
+	dicts        map[string]*dictTable // 本次导入导出已读取的字典
+	template     bool                  // 导出空白模板，数据区域解锁并预留填写行
+	headers      map[string]*Column    // 规范化后的表头和别名 / 字段
+	keepExamples bool                  // 导入时不跳过模板的示例行
+	links        int                   // 当前工作表已写入的超链接数
+	source       *zip.Reader           // 导入文件的压缩包，读取超链接时使用
 }
 
 type Option interface {
@@ -53,26 +74,72 @@ func (d *DefaultOption) apply(options *Options) {
 	options.Title = d.Title
 }
 
+type optionFunc func(options *Options)
+
+func (f optionFunc) apply(options *Options) {
+	f(options)
+}
+
+// WithCollectErrors 导入时收集全部单元格错误后以 ImportErrors 一并返回
+func WithCollectErrors() Option {
+	return optionFunc(func(options *Options) {
+		options.CollectErrors = true
+	})
+}
+
 func New(opts ...Option) *Excel {
+	return &Excel{Option: newOptions(opts...)}
+}
+
+func newOptions(opts ...Option) Options {
 	opt := Options{}
 	for _, option := range opts {
 		option.apply(&opt)
 	}
-	return &Excel{Option: opt}
+	if opt.SheetName == "" {
+		opt.SheetName = "Sheet1"
+	}
+	return opt
 }
 
-func (e *Excel) defaultStyle() {
-	titleStyle, _ := e.File.NewStyle(&excelize.Style{
-		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
-		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#DFEBF6"}, Pattern: 1},
-		Font: &excelize.Font{
-			Bold: true,
-			Size: 25,
-		},
-	})
-	e.Sw.SetRow("A1",
+// 按主题写入标题
+func (e *Excel) defaultStyle() error {
+	titleRows := e.titleRows()
+	if titleRows == 0 {
+		return nil
+	}
+	theme := e.theme()
+	style := theme.Title
+	if style != nil && titleRows > 1 {
+		// 多行标题自动换行
+		copied := *style
+		alignment := excelize.Alignment{}
+		if copied.Alignment != nil {
+			alignment = *copied.Alignment
+		}
+		alignment.WrapText = true
+		copied.Alignment = &alignment
+		style = &copied
+	}
+	titleStyle, err := e.newStyle(style)
+	if err != nil {
+		return err
+	}
+	height := theme.TitleHeight
+	if height <= 0 {
+		height = 30
+	}
+	if err := e.Sw.SetRow(e.cellName(0, 1),
 		[]any{excelize.Cell{Value: e.Option.Title, StyleID: titleStyle}},
-		excelize.RowOpts{Height: 30, Hidden: false})
+		excelize.RowOpts{Height: height, Hidden: false}); err != nil {
+		return err
+	}
+	for row := 2; row <= titleRows; row++ {
+		if err := e.Sw.SetRow(e.cellName(0, row), nil, excelize.RowOpts{Height: height}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (e *Excel) export(data any) error {
@@ -80,47 +147,94 @@ func (e *Excel) export(data any) error {
 	if err != nil {
 		return err
 	}
+	rvData, ok := e.GetEntityInfo(data)
+	if !ok {
+		return errors.New("data get entity info err")
+	}
+	return e.write(rvData)
+}
+
+// 将切片数据流式写入新建的工作簿
+func (e *Excel) write(rv reflect.Value) error {
+	return e.writeFrom(sliceRows(rv))
+}
+
+// 将 next 逐个提供的数据流式写入新建的工作簿
+func (e *Excel) writeFrom(next func() (reflect.Value, bool)) error {
 	e.File = excelize.NewFile()
-	index, err := e.File.NewSheet(e.Option.SheetName)
+	index, err := e.writeSheet(next)
 	if err != nil {
 		return err
 	}
-	e.Sw, err = e.File.NewStreamWriter(e.Option.SheetName)
-	if err != nil {
-		return err
+	e.File.SetActiveSheet(index)
+	if e.Option.SheetName != "Sheet1" {
+		_ = e.File.DeleteSheet("Sheet1")
 	}
+	return nil
+}
 
-	err = e.Sw.SetColWidth(1, 4, 20)
+// 在 e.File 中新建工作表并写入数据，返回工作表索引
+func (e *Excel) writeSheet(next func() (reflect.Value, bool)) (int, error) {
+	e.dicts, e.links = nil, 0
+	index, err := e.File.NewSheet(e.Option.SheetName)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	vCell, _ := numberToLetters(len(e.Fields))
-	if err := e.Sw.MergeCell("A1", vCell+"1"); err != nil {
-		return err
+	e.Sw, err = e.File.NewStreamWriter(e.Option.SheetName)
+	if err != nil {
+		return 0, err
 	}
 
-	e.defaultStyle()
+	if err := e.checkLayout(e.headerRow(), false); err != nil {
+		return 0, err
+	}
 
-	rvData, ok := e.GetEntityInfo(data)
-	if !ok {
-		return errors.New("data get entity info err")
+	next, sample := e.sampleRows(next)
+	offset := e.layout().ColOffset
+	for k, width := range e.columnWidths(e.columns(), sample) {
+		if width == 0 {
+			continue
+		}
+		if err := e.Sw.SetColWidth(k+1+offset, k+1+offset, width); err != nil {
+			return 0, err
+		}
+	}
+	if titleRows := e.titleRows(); titleRows > 0 {
+		if err := e.Sw.MergeCell(e.cellName(0, 1), e.cellName(len(e.Fields)-1, titleRows)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := e.defaultStyle(); err != nil {
+		return 0, err
 	}
-	err = e.SetValue(rvData)
+
+	err = e.setValues(next, fmt.Sprintf("excel_%d", index))
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if e.template {
+		if err := e.protectSheet(); err != nil {
+			return 0, err
+		}
+	}
+	if err := e.Sw.Flush(); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+func (e *Excel) close() {
+	if e.File == nil {
+		return
+	}
+	if err := e.File.Close(); err != nil {
+		return
 	}
-	e.Sw.Flush()
-	e.File.SetActiveSheet(index)
-	_ = e.File.DeleteSheet("Sheet1")
-	return nil
 }
 
 func (e *Excel) ExportToBytes(data any) ([]byte, error) {
-	defer func() {
-		if err := e.File.Close(); err != nil {
-			return
-		}
-	}()
+	defer e.close()
 	err := e.export(data)
 	if err != nil {
 		return nil, err
@@ -133,11 +247,7 @@ func (e *Excel) ExportToBytes(data any) ([]byte, error) {
 }
 
 func (e *Excel) ExportToFile(data any) error {
-	defer func() {
-		if err := e.File.Close(); err != nil {
-			return
-		}
-	}()
+	defer e.close()
 	err := e.export(data)
 	if err != nil {
 		return err
@@ -162,154 +272,378 @@ func (e *Excel) GetEntityInfo(data any) (reflect.Value, bool) {
 }
 
 func (e *Excel) Import(data []byte, result any) error {
-	r := bytes.NewReader(data)
-	f, err := excelize.OpenReader(r)
+	resv := reflect.ValueOf(result)
+	if resv.Kind() != reflect.Ptr || resv.Elem().Kind() != reflect.Slice {
+		return errors.New("result must be a pointer to slice")
+	}
+	err := e.getField(result)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	err = e.getField(result)
+	f, zr, err := openSource(bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
-	resv := reflect.ValueOf(result)
-	// 获取切片的值和元素类型
+	e.source = zr
+	defer f.Close()
+	// 获取切片的值，切片元素可以是结构体或结构体指针
 	sliceValue := resv.Elem()
-	elemType := sliceValue.Type().Elem()
-	// 如果切片元素是指针类型，获取指针指向的结构体类型
-	isPtr := false
-	if elemType.Kind() == reflect.Ptr {
-		elemType = elemType.Elem()
-		isPtr = true
+	isPtr := sliceValue.Type().Elem().Kind() == reflect.Ptr
+	return e.readRows(f, func(elem reflect.Value, _ int) error {
+		if isPtr {
+			elem = elem.Addr()
+		}
+		// 将新元素追加到切片
+		sliceValue.Set(reflect.Append(sliceValue, elem))
+		return nil
+	})
+}
+
+// 打开导入的文件，同时返回压缩包，用于读取 excelize 未提供的内容，如超链接的类型。
+// 加密的文件不是压缩包，返回的压缩包为 nil
+func openSource(r io.Reader) (*excelize.File, *zip.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
 	}
+	zr, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	return f, zr, nil
+}
 
+// 读取工作表的表头行和上方的分组行
+func (e *Excel) readHeader(f *excelize.File, sheet string) ([][]string, []string, error) {
+	headerRow, _, err := e.findHeaderRow(f, sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	groups := make([][]string, e.groupDepth())
+	for count := 1; rows.Next(); count++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return nil, nil, err
+		}
+		if level := count - headerRow + len(groups); level >= 0 && level < len(groups) {
+			groups[level] = row
+		}
+		if count == headerRow {
+			return groups, row, nil
+		}
+	}
+	return groups, nil, rows.Error()
+}
+
+// 表头映射到的列
+type mappedColumn struct {
+	*Column
+	index int // 列序号，从 0 开始
+}
+
+// 逐行读取工作表，解码为 ModelRt 类型的结构体后交给 fn 处理
+func (e *Excel) readRows(f *excelize.File, fn func(elem reflect.Value, rowNum int) error) error {
+	e.dicts = nil
+	headerRow, scanned, err := e.findHeaderRow(f, e.Option.SheetName)
+	if err != nil {
+		return err
+	}
+	if err := e.checkLayout(headerRow, scanned); err != nil {
+		return err
+	}
 	rows, err := f.Rows(e.Option.SheetName)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
+	// 字符串字段读取显示的文本，另一个迭代器与 rows 同步读取同一行
+	var texts *excelize.Rows
+	for _, col := range e.columns() {
+		if !col.readsText() {
+			continue
+		}
+		if texts, err = f.Rows(e.Option.SheetName); err != nil {
+			return err
+		}
+		defer texts.Close()
+		break
+	}
 
-	count, skip := 0, 0
-	if e.Option.ShowRemind {
-		skip = 3
-	} else {
-		skip = 2
+	// 链接列读取工作表中的超链接
+	var links map[string]string
+	for _, col := range e.columns() {
+		if !col.Link {
+			continue
+		}
+		if e.source == nil {
+			return errors.New("link columns require the source file")
+		}
+		if links, err = sheetLinks(e.source, e.Option.SheetName); err != nil {
+			return err
+		}
+		break
 	}
 
+	count, skip := 0, e.dataRow(headerRow)-1
+	var exampleFirst, exampleLast int
+	if !e.keepExamples {
+		exampleFirst, exampleLast = e.exampleRange(f)
+	}
+
+	cols := make([]mappedColumn, 0, len(e.Fields))
+	groups := make([][]string, e.groupDepth())
+	errs := make(ImportErrors, 0)
 	// 行迭代
 	for rows.Next() {
 		count++
-		// 提取字段索引
-		if count == 2 {
-			row, err := rows.Columns()
-			if err != nil {
+		// 读取原始值，数字和日期不受单元格格式影响
+		row, err := rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		var text []string
+		if texts != nil && texts.Next() {
+			if text, err = texts.Columns(); err != nil {
 				return err
 			}
-			for k, colCell := range row {
-				if _, ok := e.Rows[colCell]; ok {
-					e.Rows[colCell].Col, _ = numberToLetters(k + 1)
-				}
+		}
+		// 表头上方的分组行
+		if level := count - headerRow + len(groups); level >= 0 && level < len(groups) {
+			groups[level] = row
+		}
+		// 提取字段索引
+		if count == headerRow {
+			if cols, err = e.mapHeader(groups, row); err != nil {
+				return err
 			}
 		}
 
 		if skip >= count {
 			continue
 		}
-		newElem := reflect.New(elemType).Elem()
-
-		cell := strconv.Itoa(count)
-		for _, v := range e.Rows {
-			if v.Col == "" {
+		// 跳过模板的示例行
+		if count >= exampleFirst && count <= exampleLast {
+			continue
+		}
+		// 跳过所有单元格都为空的行，如模板中预留的填写行
+		if isBlankRow(row) {
+			continue
+		}
+		// 跳过导出时写入的合计行
+		totals, err := e.isTotalsRow(f, cols, row, count)
+		if err != nil {
+			return err
+		}
+		if totals {
+			continue
+		}
+		newElem := reflect.New(e.ModelRt).Elem()
+
+		rowErrs := make(ImportErrors, 0)
+		for _, v := range cols {
+			// 行尾的空单元格不会出现在 row 中
+			val := ""
+			switch {
+			case v.readsText():
+				if v.index < len(text) {
+					val = text[v.index]
+				}
+			case v.index < len(row):
+				val = row[v.index]
+			}
+			// 公式列读取缓存的计算结果，未经 Excel 计算保存的文件没有结果
+			if v.Formula != "" && (e.Option.SkipFormulas || val == "") {
 				continue
 			}
-			val, err := f.GetCellValue(e.Option.SheetName, v.Col+cell)
-			if err != nil {
-				return err
+			var err error
+			if v.Link {
+				val, err = e.readLink(f, links, newElem, v, val, count)
 			}
-			metaValue := convertStringToType(val, v.FieldType)
-
-			field := newElem.FieldByName(v.Field)
-
-			if !field.IsValid() {
-				return errors.New("field is not valid")
+			if err == nil {
+				err = e.setField(newElem, v.Column, val)
 			}
-
-			if !field.CanSet() {
-				return errors.New("field is not settable")
+			if err != nil {
+				importErr := &ImportError{
+					Sheet:  e.Option.SheetName,
+					Row:    count,
+					Col:    v.Col,
+					Header: v.NaturalName,
+					Value:  val,
+					Err:    err,
+				}
+				if !e.Option.CollectErrors {
+					return importErr
+				}
+				rowErrs = append(rowErrs, importErr)
 			}
-			// 将值赋给字段
-			vals := reflect.ValueOf(metaValue)
-			if !vals.Type().AssignableTo(field.Type()) {
-				return errors.New("cannot assign value of type")
+		}
+		if len(rowErrs) > 0 {
+			// 汇总模式下跳过出错的行
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		if err := fn(newElem, count); err != nil {
+			if errors.Is(err, ErrStop) {
+				break
 			}
-			field.Set(vals)
+			return err
 		}
-		if isPtr {
-			newElem = newElem.Addr()
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// 所有单元格都为空白
+func isBlankRow(row []string) bool {
+	for _, val := range row {
+		if strings.TrimSpace(val) != "" {
+			return false
 		}
-		// 将新元素追加到切片
-		sliceValue.Set(reflect.Append(sliceValue, newElem))
 	}
+	return true
+}
+
+// 将单元格的值转换后赋给字段
+func (e *Excel) setField(elem reflect.Value, col *Column, val string) error {
+	if val == "" {
+		if col.Required {
+			return ErrRequired
+		}
+		if col.Default == "" {
+			return nil
+		}
+		val = col.Default
+	}
+	field, err := fieldByIndexAlloc(elem, col.FieldIndex)
+	if err != nil {
+		return err
+	}
+	if !field.IsValid() {
+		return errors.New("field is not valid")
+	}
+	if !field.CanSet() {
+		return errors.New("field is not settable")
+	}
+	var metaValue any
+	if col.Dict != "" {
+		metaValue, err = e.dictValue(col, val)
+	} else {
+		metaValue, err = col.convert(val)
+	}
+	if err != nil {
+		return err
+	}
+	// 将值赋给字段
+	vals := reflect.ValueOf(metaValue)
+	if !vals.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("cannot assign value of type %s to %s", vals.Type(), field.Type())
+	}
+	field.Set(vals)
 	return nil
 }
 
 func (e *Excel) SetValue(rv reflect.Value) error {
-	rangeBottoms := "B"
-	colList := make([]string, len(e.Fields))
-	rangeBottoms, _ = numberToLetters(len(colList))
-	rangeBottoms = rangeBottoms + strconv.Itoa(rv.Len()+2)
-	for k, v := range e.Fields {
-		colList[v.Index] = k
-	}
-	for i := 2; i < rv.Len()+3; i++ {
-		vals := make([]any, 0)
-		cell, _ := excelize.CoordinatesToCellName(1, i)
-		for _, col := range colList {
-			var cellValue any
-			if i == 2 {
-				cellValue = e.Fields[col].NaturalName
-			} else {
-				index := i - 3
-				rval := reflect.ValueOf(rv.Index(index).Interface())
-				if rval.Kind() == reflect.Ptr {
-					rval = rval.Elem()
-				}
-				rfval := rval.FieldByName(e.Fields[col].Field)
-				if !rfval.IsZero() {
-					x := rfval.Interface()
-					cellValue = x
-					if e.Fields[col].IsImage {
-						// 处理图片字段
-						imgPath := rfval.String()
-						imgData, err := ReadFile(imgPath)
-						if err == nil {
-							err = e.setImage(cell, imgData.Extension, imgData.Data)
-							if err != nil {
-								fmt.Printf("Failed to set image: %v\n", err)
-							}
-						}
-						cellValue = "" // 图片单元格内容置空
-						continue
-					}
+	return e.setValues(sliceRows(rv), "excel")
+}
 
-					if e.Fields[col].FieldType == reflect.TypeOf(time.Time{}) {
-						cellTime := cellValue.(time.Time)
-						cellValue = cellTime.Format("2006-01-02 15:04:05")
-					}
-				} else {
-					cellValue = ""
-				}
+// 按顺序逐个返回切片元素
+func sliceRows(rv reflect.Value) func() (reflect.Value, bool) {
+	i := 0
+	return func() (reflect.Value, bool) {
+		if i >= rv.Len() {
+			return reflect.Value{}, false
+		}
+		i++
+		return rv.Index(i - 1), true
+	}
+}
+
+// 写入表头和数据行，写完最后一行后再确定表格范围
+func (e *Excel) setValues(next func() (reflect.Value, bool), tableName string) error {
+	cols := e.columns()
+	headerRow := e.headerRow()
+	if err := e.writeHeader(cols); err != nil {
+		return err
+	}
+	styles, err := e.columnStyles(cols)
+	if err != nil {
+		return err
+	}
+	formulas, err := e.formulas(cols)
+	if err != nil {
+		return err
+	}
+	styleCache, err := e.newStyleCache(cols)
+	if err != nil {
+		return err
+	}
+	firstRow := e.dataRow(headerRow)
+	rowNum := firstRow - 1
+	for {
+		item, ok := next()
+		if !ok {
+			break
+		}
+		rowNum++
+		vals, err := e.rowValues(cols, styles, formulas, item, rowNum)
+		if err != nil {
+			return err
+		}
+		if styleCache != nil {
+			if err := e.styleRow(styleCache, cols, vals, item, rowNum-firstRow); err != nil {
+				return err
 			}
-			vals = append(vals, cellValue)
 		}
-		err := e.Sw.SetRow(cell, vals)
+		if err := e.Sw.SetRow(e.cellName(0, rowNum), vals); err != nil {
+			return err
+		}
+	}
+	dataRows := rowNum
+	if e.template && dataRows >= firstRow {
+		if err := e.markExampleRows(firstRow, dataRows); err != nil {
+			return err
+		}
+	}
+	_, hasTotals := totalsLabelColumn(cols)
+	if e.template || hasTotals {
+		// 模板和合计行上方预留可以填写的空行，行样式不锁定，导入时跳过
+		unlocked, err := e.File.NewStyle(&excelize.Style{Protection: &excelize.Protection{Locked: false}})
 		if err != nil {
 			return err
 		}
+		for i := 0; i < e.Option.ValidationRows; i++ {
+			rowNum++
+			if err := e.Sw.SetRow(e.cellName(0, rowNum), nil, excelize.RowOpts{StyleID: unlocked}); err != nil {
+				return err
+			}
+		}
+	}
+	// 数据验证和合计覆盖数据下方预留的空行，合计行在空行下方
+	lastRow := max(dataRows, firstRow) + max(e.Option.ValidationRows, 0)
+	tableTotals := hasTotals && e.Option.TableTotals
+	if hasTotals {
+		if err := e.writeTotals(cols, lastRow+1, firstRow, lastRow, tableName); err != nil {
+			return err
+		}
+	}
+	// 表格至少需要包含表头在内的两行，使用内置合计行时表格延伸到合计行上方
+	tableBottom := max(rowNum, headerRow+1)
+	if tableTotals {
+		tableBottom = lastRow
 	}
-	err := e.Sw.AddTable(&excelize.Table{
-		Range:             "A2:" + rangeBottoms,
-		Name:              "excel",
-		StyleName:         "TableStyleMedium2",
+	tableRef := e.cellName(0, headerRow) + ":" + e.cellName(len(cols)-1, tableBottom)
+	err = e.Sw.AddTable(&excelize.Table{
+		Range:             tableRef,
+		Name:              tableName,
+		StyleName:         e.theme().TableStyle,
 		ShowFirstColumn:   true,
 		ShowLastColumn:    true,
 		ShowColumnStripes: true,
@@ -317,7 +651,179 @@ func (e *Excel) SetValue(rv reflect.Value) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	if tableTotals {
+		totalsRef := e.cellName(0, headerRow) + ":" + e.cellName(len(cols)-1, lastRow+1)
+		if err := e.addTableTotals(cols, tableName, tableRef, totalsRef); err != nil {
+			return err
+		}
+	}
+	if err := e.addValidations(cols, firstRow, lastRow); err != nil {
+		return err
+	}
+	// 条件格式只覆盖数据行，避免空行参与重复值、色阶等规则
+	return e.addConditionalFormats(cols, firstRow, dataRows)
+}
+
+// 写入分组表头、表头和提示行
+func (e *Excel) writeHeader(cols []*Column) error {
+	headerRow := e.headerRow()
+	depth := e.groupDepth()
+	if depth > 0 {
+		groupStyle, err := e.newStyle(e.theme().Group)
+		if err != nil {
+			return err
+		}
+		for level := 0; level < depth; level++ {
+			row := headerRow - depth + level
+			vals := make([]any, len(cols))
+			for k := 0; k < len(cols); {
+				// 相邻且上级分组相同的列合并为一个分组单元格
+				end := k + 1
+				if len(cols[k].Groups) > level {
+					for end < len(cols) && sameGroup(cols[k], cols[end], level) {
+						end++
+					}
+				}
+				for i := k; i < end; i++ {
+					vals[i] = excelize.Cell{StyleID: groupStyle}
+				}
+				if len(cols[k].Groups) > level {
+					vals[k] = excelize.Cell{Value: cols[k].Groups[level], StyleID: groupStyle}
+					if end-k > 1 {
+						if err := e.Sw.MergeCell(e.cellName(k, row), e.cellName(end-1, row)); err != nil {
+							return err
+						}
+					}
+				}
+				k = end
+			}
+			if err := e.Sw.SetRow(e.cellName(0, row), vals); err != nil {
+				return err
+			}
+		}
+	}
+
+	headerStyle, err := e.newStyle(e.theme().Header)
+	if err != nil {
+		return err
+	}
+	header := make([]any, len(cols))
+	for k, col := range cols {
+		header[k] = excelize.Cell{Value: col.NaturalName, StyleID: headerStyle}
+	}
+	if err := e.Sw.SetRow(e.cellName(0, headerRow), header); err != nil {
+		return err
+	}
+	if !e.Option.ShowRemind {
+		return nil
+	}
+	return e.writeRemind(cols, headerRow+1)
+}
+
+// 两列在 level 及以上的分组是否相同
+func sameGroup(a, b *Column, level int) bool {
+	if len(a.Groups) <= level || len(b.Groups) <= level {
+		return false
+	}
+	for i := 0; i <= level; i++ {
+		if a.Groups[i] != b.Groups[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 取出一条数据各字段的单元格值
+func (e *Excel) rowValues(cols []*Column, styles []int, formulas []string, item reflect.Value, rowNum int) ([]any, error) {
+	rval := structValue(item)
+	vals := make([]any, len(cols))
+	var formulaLinks map[int]string
+	for k, col := range cols {
+		vals[k] = excelize.Cell{StyleID: styles[k]}
+		if formulas != nil && formulas[k] != "" {
+			// 公式列不使用字段的值
+			vals[k] = excelize.Cell{Formula: rowFormula(formulas[k], rowNum), StyleID: styles[k]}
+			continue
+		}
+		if col.Link {
+			if target := linkTarget(rval, col); target != "" {
+				ok, err := e.setLink(e.cellName(k, rowNum), target)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					if formulaLinks == nil {
+						formulaLinks = make(map[int]string)
+					}
+					formulaLinks[k] = target
+				}
+				// 显示的字段为空时显示链接地址
+				vals[k] = excelize.Cell{Value: strings.TrimPrefix(target, "#"), StyleID: styles[k]}
+			}
+		}
+		rfval, ok := fieldValue(rval, col)
+		if !ok {
+			continue
+		}
+		if col.IsImage {
+			// 处理图片字段，图片单元格内容置空
+			imgData, err := ReadFile(rfval.String())
+			if err == nil {
+				err = e.setImage(e.cellName(k, rowNum), imgData.Extension, imgData.Data)
+				if err != nil {
+					fmt.Printf("Failed to set image: %v\n", err)
+				}
+			}
+			continue
+		}
+		cellValue, err := e.cellValue(col, rfval)
+		if err != nil {
+			return nil, err
+		}
+		vals[k] = excelize.Cell{Value: cellValue, StyleID: styles[k]}
+	}
+	// 超链接超出上限的单元格改用公式，缓存值为显示的内容
+	for k, target := range formulaLinks {
+		cell := vals[k].(excelize.Cell)
+		cell.Formula = linkFormula(target, cell.Value)
+		vals[k] = cell
+	}
+	return vals, nil
+}
+
+// 去掉接口和指针，取出数据行的结构体
+func structValue(item reflect.Value) reflect.Value {
+	for item.Kind() == reflect.Interface || item.Kind() == reflect.Ptr {
+		item = item.Elem()
+	}
+	return item
+}
+
+// 取出列对应的字段值，路径上有空指针时返回 false，零值只在设置了导出转换器或字典时返回
+func fieldValue(rval reflect.Value, col *Column) (reflect.Value, bool) {
+	if !rval.IsValid() {
+		return reflect.Value{}, false
+	}
+	rfval, err := rval.FieldByIndexErr(col.FieldIndex)
+	if err != nil || (rfval.IsZero() && col.ExportFunc == nil && col.Dict == "") {
+		return reflect.Value{}, false
+	}
+	return rfval, true
+}
+
+// 将字段值转换为写入单元格的值
+func (e *Excel) cellValue(col *Column, rfval reflect.Value) (any, error) {
+	if col.Dict != "" {
+		return e.dictLabel(col, rfval)
+	}
+	if col.ExportFunc == nil {
+		return toCellValue(rfval)
+	}
+	v, err := col.ExportFunc.Convert(rfval.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("convert field %s: %w", col.Field, err)
+	}
+	return toCellValue(reflect.ValueOf(v))
 }
 
 func (e *Excel) report() error {
@@ -511,25 +1017,3 @@ func refType(value any) (val any, is bool) {
 		return nil, false
 	}
 }
-
-func convertStringToType(val string, typ reflect.Type) any {
-	switch typ.Kind() {
-	case reflect.String:
-		return cast.ToString(val)
-	case reflect.Int64:
-		return cast.ToInt64(val)
-	case reflect.Int:
-		return cast.ToInt(val)
-	case reflect.Bool:
-		return cast.ToBool(val)
-	case reflect.Float64:
-		return cast.ToFloat64(val)
-	case reflect.Struct:
-		if reflect.TypeOf(time.Time{}) == typ {
-			return cast.ToTimeInDefaultLocation(val, time.Local)
-		}
-		return val
-	default:
-		return reflect.Zero(typ).Interface()
-	}
-}
@@ -1,9 +1,13 @@
 package go_excel
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/xuri/excelize/v2"
 )
 
 type Person struct {
@@ -72,6 +76,34 @@ func Test_refType(t *testing.T) {
 	}
 }
 
+func TestExcel_getFieldCache(t *testing.T) {
+	type Cached struct {
+		Name string `excel:"姓名"`
+	}
+	first, second := New(), New()
+	if err := first.getField([]Cached{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.getField(&[]*Cached{{Name: "Jason"}}); err != nil {
+		t.Fatal(err)
+	}
+	if first.Fields["Name"] != second.Fields["Name"] {
+		t.Error("getField() want columns parsed once per type")
+	}
+	// 注册转换器后重新解析
+	RegisterParser("cache_test", nil, nil)
+	third := New()
+	if err := third.getField([]Cached{}); err != nil {
+		t.Fatal(err)
+	}
+	if third.Fields["Name"] == first.Fields["Name"] {
+		t.Error("getField() want columns parsed again after registering a converter")
+	}
+	if err := New().getField([]int{}); err == nil {
+		t.Error("getField() want error for non-struct elements")
+	}
+}
+
 func TestExcel_ReportFromFile(t *testing.T) {
 	type Po struct {
 		OrderNo string
@@ -219,3 +251,56 @@ func TestExcel_ExportToBytes(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestExcel_ImportCollectErrors(t *testing.T) {
+	type Person struct {
+		Name string `excel:"姓名"`
+		Age  int    `excel:"年龄"`
+	}
+	people := []Person{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}, {Name: "Tom", Age: 30}}
+	dataBuf, err := New(&DefaultOption{SheetName: "Ye", Title: "Y01"}).ExportToBytes(&people)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(dataBuf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.SetCellValue("Ye", "B3", "abc")
+	_ = f.SetCellValue("Ye", "B5", "x1")
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantErrs int
+		wantRows int
+	}{
+		{"fail fast", []Option{&DefaultOption{SheetName: "Ye"}}, 1, 0},
+		{"collect", []Option{&DefaultOption{SheetName: "Ye"}, WithCollectErrors()}, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := make([]Person, 0)
+			err := New(tt.opts...).Import(buf.Bytes(), &list)
+			var importErr *ImportError
+			if !errors.As(err, &importErr) {
+				t.Fatalf("Import() error = %v, want *ImportError", err)
+			}
+			if importErr.Row != 3 || importErr.Col != "B" || importErr.Header != "年龄" || importErr.Value != "abc" {
+				t.Errorf("Import() first error = %+v", importErr)
+			}
+			var importErrs ImportErrors
+			if errors.As(err, &importErrs) && len(importErrs) != tt.wantErrs {
+				t.Errorf("Import() got %d errors, want %d", len(importErrs), tt.wantErrs)
+			}
+			if tt.wantRows > 0 && len(list) != tt.wantRows {
+				t.Errorf("Import() got %d rows, want %d", len(list), tt.wantRows)
+			}
+			t.Log(err)
+		})
+	}
+}
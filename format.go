@@ -0,0 +1,84 @@
+package go_excel
+
+import "github.com/xuri/excelize/v2"
+
+const (
+	defaultDateFormat     = "yyyy-mm-dd hh:mm:ss" // 日期列未设置 format 时的默认格式
+	defaultDurationFormat = "[h]:mm:ss"           // 时长列未设置 format 时的默认格式
+)
+
+// 常用数字格式的别名，可以直接用于 format 标签，如 excel:"金额,format=currency"
+var numFmtAliases = map[string]string{
+	"integer":   "0",
+	"decimal":   "0.00",
+	"thousands": "#,##0",
+	"currency":  "¥#,##0.00",
+	"percent":   "0.00%",
+	"date":      "yyyy-mm-dd",
+	"datetime":  "yyyy-mm-dd hh:mm:ss",
+	"time":      "hh:mm:ss",
+	"text":      "@",
+}
+
+// WithDateFormat 设置日期列的默认显示格式，如 yyyy/mm/dd
+func WithDateFormat(format string) Option {
+	return optionFunc(func(options *Options) {
+		options.DateFormat = format
+	})
+}
+
+// 列的数字格式，别名会被替换为实际格式
+func (e *Excel) numFmt(col *Column) string {
+	format := col.Format
+	if format == "" {
+		switch valueType(col.FieldType) {
+		case timeType:
+			format = e.Option.DateFormat
+			if format == "" {
+				format = defaultDateFormat
+			}
+		case durationType:
+			format = defaultDurationFormat
+		}
+	}
+	if v, ok := numFmtAliases[format]; ok {
+		return v
+	}
+	return format
+}
+
+// 列的基础样式，包含数字格式和超链接的字体，模板的数据区域不锁定，都没有时返回 nil
+func (e *Excel) columnStyle(col *Column) *excelize.Style {
+	format := e.numFmt(col)
+	if format == "" && !col.Link && !e.template {
+		return nil
+	}
+	style := &excelize.Style{}
+	if format != "" {
+		style.CustomNumFmt = &format
+	}
+	if col.Link {
+		style.Font = linkFont
+	}
+	if e.template {
+		style.Protection = &excelize.Protection{Locked: false}
+	}
+	return style
+}
+
+// 为有基础样式的列创建样式，返回各列的样式 ID
+func (e *Excel) columnStyles(cols []*Column) ([]int, error) {
+	styles := make([]int, len(cols))
+	for k, col := range cols {
+		style := e.columnStyle(col)
+		if style == nil {
+			continue
+		}
+		styleID, err := e.File.NewStyle(style)
+		if err != nil {
+			return nil, err
+		}
+		styles[k] = styleID
+	}
+	return styles, nil
+}
@@ -0,0 +1,116 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExcel_NativeCells(t *testing.T) {
+	type Row struct {
+		Name     string    `excel:"名称"`
+		Created  time.Time `excel:"创建时间"`
+		Birthday time.Time `excel:"生日,format=date"`
+		Amount   float64   `excel:"金额,format='#,##0.00'"`
+		Rate     float64   `excel:"比例,format=percent"`
+		Count    int       `excel:"数量"`
+	}
+	rows := []Row{
+		{"A", time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local), time.Date(1990, 1, 2, 0, 0, 0, 0, time.Local), 12345.678, 0.125, 3},
+		{"B", time.Time{}, time.Time{}, 0, 1, 0},
+	}
+	c, err := NewCodec[Row](WithDateFormat("yyyy/mm/dd hh:mm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Export(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"B3", "2024/05/06 07:08"},
+		{"C3", "1990-01-02"},
+		{"D3", "12,345.68"},
+		{"E3", "12.50%"},
+		{"F3", "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			// 原始值是数字或日期序列号
+			raw, err := f.GetCellValue("Sheet1", tt.cell, excelize.Options{RawCellValue: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := strconv.ParseFloat(raw, 64); err != nil {
+				t.Errorf("raw value %q is not a number", raw)
+			}
+			got, err := f.GetCellValue("Sheet1", tt.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCellValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	got, err := c.Import(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+}
+
+func TestImport_FormattedText(t *testing.T) {
+	type Row struct {
+		Day    string  `excel:"日期"`
+		Amount string  `excel:"金额"`
+		Value  float64 `excel:"数值"`
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetRow("Sheet1", "A2", &[]any{"日期", "金额", "数值"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetRow("Sheet1", "A3", &[]any{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), 1.5, 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	dateFmt, decimalFmt := "yyyy-mm-dd", "0.00"
+	for cell, format := range map[string]*string{"A3": &dateFmt, "B3": &decimalFmt, "C3": &decimalFmt} {
+		style, err := f.NewStyle(&excelize.Style{CustomNumFmt: format})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SetCellStyle("Sheet1", cell, cell, style); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 字符串字段读取显示的文本，数字字段读取原始值
+	got, err := Import[Row](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{{"2024-01-02", "1.50", 1.5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
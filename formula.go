@@ -0,0 +1,68 @@
+package go_excel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 公式中的占位符，{row} 为当前行号，{字段名} 或 {表头} 替换为该列的列名，
+// 数组常量如 {1,2;"a","b"} 保持不变，其他占位符报错，如
+//
+//	excel:"小计,formula='{数量}{row}*{单价}{row}'"
+//	excel:"小计,formula='=C{row}*D{row}'"
+var formulaPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// 数组常量的内容，由数字、字符串和逻辑值以 , 或 ; 分隔
+var arrayConstant = func() *regexp.Regexp {
+	item := `\s*(?:-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?|"(?:[^"]|"")*"|TRUE|FALSE)\s*`
+	return regexp.MustCompile(`^` + item + `(?:[,;]` + item + `)*$`)
+}()
+
+// WithSkipFormulas 导入时跳过公式列，默认读取公式缓存的计算结果
+func WithSkipFormulas() Option {
+	return optionFunc(func(options *Options) {
+		options.SkipFormulas = true
+	})
+}
+
+// 展开公式中的列占位符，返回各列的公式，没有公式列时返回 nil
+func (e *Excel) formulas(cols []*Column) ([]string, error) {
+	var formulas []string
+	for k, col := range cols {
+		if col.Formula == "" {
+			continue
+		}
+		if formulas == nil {
+			formulas = make([]string, len(cols))
+		}
+		var err error
+		formulas[k] = formulaPlaceholder.ReplaceAllStringFunc(col.Formula, func(m string) string {
+			name := m[1 : len(m)-1]
+			if name == "row" || arrayConstant.MatchString(name) {
+				return m
+			}
+			i := slices.IndexFunc(cols, func(c *Column) bool {
+				return c.Field == name || c.NaturalName == name
+			})
+			if i == -1 {
+				if err == nil {
+					err = fmt.Errorf("formula of %s refers to unknown column %q", col.NaturalName, name)
+				}
+				return m
+			}
+			return e.colName(i)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return formulas, nil
+}
+
+// 将公式中的 {row} 替换为行号
+func rowFormula(formula string, row int) string {
+	return strings.ReplaceAll(formula, "{row}", strconv.Itoa(row))
+}
@@ -0,0 +1,145 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type formulaLine struct {
+	Name  string  `excel:"名称"`
+	Qty   int     `excel:"数量"`
+	Price float64 `excel:"单价"`
+	Total float64 `excel:"小计,formula='{Qty}{row}*{单价}{row}'"`
+	Tax   float64 `excel:"税额,formula='=D{row}*0.13'"`
+}
+
+func TestExcel_Formula(t *testing.T) {
+	rows := []formulaLine{{"a", 2, 1.5, 99, 0}, {"b", 3, 2, 0, 0}}
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{"placeholders", nil, map[string]string{
+			"D3": "B3*C3", "D4": "B4*C4", "E3": "D3*0.13",
+		}},
+		{"col offset", []Option{&Layout{ColOffset: 1}}, map[string]string{
+			"E3": "C3*D3", "E4": "C4*D4", "F4": "D4*0.13",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{&DefaultOption{SheetName: "people"}}, tt.opts...)
+			data, err := Export(rows, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			for cell, want := range tt.want {
+				if got, _ := f.GetCellFormula("people", cell); got != want {
+					t.Errorf("formula %s = %q, want %q", cell, got, want)
+				}
+			}
+		})
+	}
+
+	// 数组常量保持不变
+	type Array struct {
+		Qty   int     `excel:"数量"`
+		Total float64 `excel:"小计,formula='SUMPRODUCT({数量}{row}*{1,-2.5;\"a\"\"b\",TRUE})'"`
+	}
+	data, err := Export([]Array{{1, 0}}, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, _ := f.GetCellFormula("people", "B3")
+	if want := `SUMPRODUCT(A3*{1,-2.5;"a""b",TRUE})`; got != want {
+		t.Errorf("formula B3 = %q, want %q", got, want)
+	}
+
+	type Bad struct {
+		Total float64 `excel:"小计,formula='{数量}{row}'"`
+	}
+	if _, err := Export([]Bad{{1}}); err == nil {
+		t.Error("Export() want error for unknown column in formula")
+	}
+}
+
+func TestExcel_ImportFormula(t *testing.T) {
+	data, err := Export([]formulaLine{{"a", 2, 1.5, 0, 0}}, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 模拟 Excel 保存后的文件，公式单元格带有计算结果
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ cell, formula string }{{"D3", "B3*C3"}, {"E3", "D3*0.13"}} {
+		result, err := f.CalcCellValue("people", c.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := strconv.ParseFloat(result, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SetCellValue("people", c.cell, v); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SetCellFormula("people", c.cell, c.formula); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+		opts []Option
+		want formulaLine
+	}{
+		{"no cached value", data, nil, formulaLine{"a", 2, 1.5, 0, 0}},
+		{"cached value", buf.Bytes(), nil, formulaLine{"a", 2, 1.5, 3, 0.39}},
+		{"skip formulas", buf.Bytes(), []Option{WithSkipFormulas()}, formulaLine{"a", 2, 1.5, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{&DefaultOption{SheetName: "people"}}, tt.opts...)
+			got, err := Import[formulaLine](bytes.NewReader(tt.data), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, []formulaLine{tt.want}) {
+				t.Errorf("Import() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumn_Formula(t *testing.T) {
+	type Bad struct {
+		Status int `excel:"状态,dict=formula_status,formula='1+1'"`
+	}
+	RegisterDict("formula_status", DictItem{1, "启用"})
+	if _, err := NewCodec[Bad](); err == nil {
+		t.Error("NewCodec() want error for formula on dict column")
+	}
+}
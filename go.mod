@@ -1,10 +1,11 @@
 module github.com/NebulaLinkhub/go-excel
 
-go 1.22.6
+go 1.23
 
 require (
 	github.com/spf13/cast v1.7.0
 	github.com/xuri/excelize/v2 v2.9.0
+	golang.org/x/text v0.21.0
 )
 
 require (
@@ -15,5 +16,4 @@ require (
 	github.com/xuri/nfp v0.0.0-20240318013403-ab9948c2c4a7 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/net v0.33.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
 )
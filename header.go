@@ -0,0 +1,137 @@
+package go_excel
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// WithStrictHeader 导入时表头必须与结构体一致，缺少必填列、存在未知列或重复列时返回 HeaderError
+func WithStrictHeader() Option {
+	return optionFunc(func(options *Options) {
+		options.StrictHeader = true
+	})
+}
+
+// 规范化表头：全角转半角，去掉空白，转小写
+func normalizeHeader(s string) string {
+	var b strings.Builder
+	for _, r := range width.Fold.String(s) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// 按表头查找列，先精确匹配，再按规范化后的表头和别名匹配
+func (e *Excel) lookupHeader(name string) (*Column, bool) {
+	if v, ok := e.Rows[name]; ok {
+		return v, true
+	}
+	v, ok := e.headers[normalizeHeader(name)]
+	return v, ok
+}
+
+// 将表头行映射到列，groups 为表头上方的分组行，按字段顺序返回，同一字段出现多次时使用第一列
+func (e *Excel) mapHeader(groups [][]string, header []string) ([]mappedColumn, error) {
+	cols := make([]mappedColumn, 0, len(e.Fields))
+	seen := make(map[*Column]bool, len(e.Fields))
+	paths := groupPaths(groups, len(header))
+	var unknown, duplicate []string
+	for k, name := range header {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		var v *Column
+		ok := false
+		for _, path := range paths[k] {
+			if v, ok = e.lookupHeader(headerKey(path, name)); ok {
+				break
+			}
+		}
+		switch {
+		case !ok:
+			unknown = append(unknown, name)
+			continue
+		case seen[v]:
+			duplicate = append(duplicate, name)
+			continue
+		}
+		seen[v] = true
+		col := *v
+		col.Col, _ = numberToLetters(k + 1)
+		cols = append(cols, mappedColumn{Column: &col, index: k})
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		return cols[i].Index < cols[j].Index
+	})
+	if !e.Option.StrictHeader {
+		return cols, nil
+	}
+	var missing []string
+	for _, col := range e.columns() {
+		if col.Required && !seen[col] {
+			missing = append(missing, col.NaturalName)
+		}
+	}
+	if len(missing) > 0 || len(unknown) > 0 || len(duplicate) > 0 {
+		return nil, &HeaderError{Sheet: e.Option.SheetName, Missing: missing, Unknown: unknown, Duplicate: duplicate}
+	}
+	return cols, nil
+}
+
+// 分组行中每列可能的分组路径，较长的在前，最后为没有分组。
+// 合并的分组单元格只有第一个单元格有值，空单元格可能属于左侧上级分组相同的分组，也可能没有分组
+func groupPaths(groups [][]string, n int) [][][]string {
+	depth := len(groups)
+	filled := make([][]string, depth)
+	explicit := make([][]bool, depth)
+	for level, row := range groups {
+		filled[level] = make([]string, n)
+		explicit[level] = make([]bool, n)
+		for k := 0; k < n; k++ {
+			if k < len(row) && strings.TrimSpace(row[k]) != "" {
+				filled[level][k], explicit[level][k] = strings.TrimSpace(row[k]), true
+				continue
+			}
+			if k > 0 && sameParents(filled, level, k-1, k) {
+				filled[level][k] = filled[level][k-1]
+			}
+		}
+	}
+	paths := make([][][]string, n)
+	for k := 0; k < n; k++ {
+		top, least := 0, 0
+		for top < depth && filled[top][k] != "" {
+			top++
+		}
+		for level := 0; level < depth; level++ {
+			if explicit[level][k] {
+				least = level + 1
+			}
+		}
+		for m := top; m >= least && m > 0; m-- {
+			path := make([]string, m)
+			for level := range path {
+				path[level] = filled[level][k]
+			}
+			paths[k] = append(paths[k], path)
+		}
+		paths[k] = append(paths[k], nil)
+	}
+	return paths
+}
+
+// 两列在 level 以上的分组相同
+func sameParents(filled [][]string, level, a, b int) bool {
+	for i := 0; i < level; i++ {
+		if filled[i][a] != filled[i][b] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type headerUser struct {
+	Name  string `excel:"姓名,alias=名字|Name,required"`
+	Phone string `excel:"电话(手机)"`
+	Age   int    `excel:"年龄"`
+}
+
+// 生成只有表头和一行数据的工作簿，布局与导出一致
+func headerSheet(t *testing.T, header []any, row []any) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetRow("Sheet1", "A2", &header); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetRow("Sheet1", "A3", &row); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNormalizeHeader(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"姓名", "姓名"},
+		{" 姓 名 ", "姓名"},
+		{"电话（手机）", "电话(手机)"},
+		{"Ｎａｍｅ", "name"},
+		{"E-Mail　", "e-mail"},
+	}
+	for _, tt := range tests {
+		if got := normalizeHeader(tt.in); got != tt.want {
+			t.Errorf("normalizeHeader(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestImport_HeaderMatching(t *testing.T) {
+	want := []headerUser{{"Jason", "13800000000", 20}}
+	tests := []struct {
+		name   string
+		header []any
+	}{
+		{"exact", []any{"姓名", "电话(手机)", "年龄"}},
+		{"alias", []any{"名字", "电话(手机)", "年龄"}},
+		{"alias case", []any{"NAME", "电话(手机)", "年龄"}},
+		{"spaces", []any{"姓名 ", " 电话 (手机)", "年 龄"}},
+		{"full width", []any{"姓名", "电话（手机）", "年龄"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := headerSheet(t, tt.header, []any{"Jason", "13800000000", 20})
+			got, err := Import[headerUser](bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Import() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestImport_StrictHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []any
+		wantErr *HeaderError
+	}{
+		{"match", []any{"Name", "电话(手机)", "年龄"}, nil},
+		{"optional missing", []any{"姓名", "年龄"}, nil},
+		{"required missing", []any{"电话(手机)", "年龄"}, &HeaderError{Sheet: "Sheet1", Missing: []string{"姓名"}}},
+		{"unknown", []any{"姓名", "年龄", "备注"}, &HeaderError{Sheet: "Sheet1", Unknown: []string{"备注"}}},
+		{"duplicate", []any{"姓名", "名字"}, &HeaderError{Sheet: "Sheet1", Duplicate: []string{"名字"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := headerSheet(t, tt.header, []any{"Jason"})
+			_, err := Import[headerUser](bytes.NewReader(data), WithStrictHeader())
+			var headerErr *HeaderError
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Import() error = %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &headerErr) || !reflect.DeepEqual(headerErr, tt.wantErr) {
+				t.Errorf("Import() error = %#v, want %#v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 非严格模式下忽略未知列
+	data := headerSheet(t, []any{"姓名", "备注"}, []any{"Jason", "x"})
+	if _, err := Import[headerUser](bytes.NewReader(data)); err != nil {
+		t.Errorf("Import() error = %v", err)
+	}
+}
+
+func TestParseFields_HeaderConflict(t *testing.T) {
+	type Alias struct {
+		Name  string `excel:"姓名"`
+		Alias string `excel:"名称,alias=姓名"`
+	}
+	type Normalized struct {
+		Name  string `excel:"Name"`
+		Alias string `excel:"NAME"`
+	}
+	if _, err := NewCodec[Alias](); err == nil {
+		t.Error("NewCodec() want error for alias conflict")
+	}
+	if _, err := NewCodec[Normalized](); err == nil {
+		t.Error("NewCodec() want error for normalized header conflict")
+	}
+}
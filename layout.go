@@ -0,0 +1,128 @@
+package go_excel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 自动识别表头时扫描的行数
+const maxHeaderScan = 20
+
+// Layout 工作表的布局，导入和导出共用，行号从 1 开始，零值为默认布局：
+// 第 1 行为标题，之后是分组表头、表头、提示行，数据紧接其后，从 A 列开始
+type Layout struct {
+	NoTitle    bool // 不写标题行
+	TitleRows  int  // 标题合并的行数，默认 1，标题中的换行符按多行显示
+	HeaderRow  int  // 表头所在行，分组表头在其上方，0 表示紧接标题
+	AutoHeader bool // 导入时在前 20 行中查找与表头匹配最多的一行作为表头
+	ColOffset  int  // 导出时第一列前空出的列数
+	DataRow    int  // 数据起始行，0 表示紧接表头和提示行
+}
+
+func (l *Layout) apply(options *Options) {
+	layout := *l
+	options.Layout = &layout
+}
+
+func (e *Excel) layout() Layout {
+	if e.Option.Layout == nil {
+		return Layout{}
+	}
+	return *e.Option.Layout
+}
+
+// 标题占用的行数
+func (e *Excel) titleRows() int {
+	l := e.layout()
+	if l.NoTitle {
+		return 0
+	}
+	return max(l.TitleRows, 1)
+}
+
+// 表头所在行，标题之后是分组表头
+func (e *Excel) headerRow() int {
+	if l := e.layout(); l.HeaderRow > 0 {
+		return l.HeaderRow
+	}
+	return e.titleRows() + e.groupDepth() + 1
+}
+
+// 表头为 headerRow 时数据的起始行
+func (e *Excel) dataRow(headerRow int) int {
+	if l := e.layout(); l.DataRow > 0 {
+		return l.DataRow
+	}
+	if e.Option.ShowRemind {
+		return headerRow + 2
+	}
+	return headerRow + 1
+}
+
+// 检查表头和数据的位置是否与标题、分组表头和提示行重叠，
+// scanned 为自动识别的表头行，文件中不一定有标题，不检查与标题的重叠
+func (e *Excel) checkLayout(headerRow int, scanned bool) error {
+	if l := e.layout(); l.ColOffset < 0 || l.HeaderRow < 0 || l.DataRow < 0 || l.TitleRows < 0 {
+		return errors.New("layout values must not be negative")
+	}
+	if top := headerRow - e.groupDepth(); !scanned && top <= e.titleRows() {
+		return fmt.Errorf("header row %d overlaps the title", headerRow)
+	}
+	minDataRow := headerRow + 1
+	if e.Option.ShowRemind {
+		minDataRow++
+	}
+	if dataRow := e.dataRow(headerRow); dataRow < minDataRow {
+		return fmt.Errorf("data row %d overlaps the header", dataRow)
+	}
+	return nil
+}
+
+// 第 k 列（从 0 开始）在第 row 行的单元格名称，包含列偏移
+func (e *Excel) cellName(k, row int) string {
+	cell, _ := excelize.CoordinatesToCellName(k+1+e.layout().ColOffset, row)
+	return cell
+}
+
+// 第 k 列（从 0 开始）的列名，包含列偏移
+func (e *Excel) colName(k int) string {
+	name, _ := excelize.ColumnNumberToName(k + 1 + e.layout().ColOffset)
+	return name
+}
+
+// 查找工作表的表头行，开启 AutoHeader 时取前若干行中匹配列数最多的一行，并返回 true
+func (e *Excel) findHeaderRow(f *excelize.File, sheet string) (int, bool, error) {
+	if !e.layout().AutoHeader {
+		return e.headerRow(), false, nil
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+	best, bestScore := 0, 0
+	for count := 1; count <= maxHeaderScan && rows.Next(); count++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return 0, false, err
+		}
+		matched := make(map[*Column]bool)
+		for _, name := range row {
+			if v, ok := e.lookupHeader(name); ok {
+				matched[v] = true
+			}
+		}
+		if len(matched) > bestScore {
+			best, bestScore = count, len(matched)
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return 0, false, err
+	}
+	if best == 0 {
+		return 0, false, fmt.Errorf("sheet %q: header row not found", sheet)
+	}
+	return best, true, nil
+}
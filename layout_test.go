@@ -0,0 +1,151 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestLayout_RoundTrip(t *testing.T) {
+	people := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}}
+	tests := []struct {
+		name   string
+		layout *Layout
+		remind bool
+		cells  map[string]string
+	}{
+		{"default", &Layout{}, false, map[string]string{"A1": "People", "A2": "姓名", "A3": "Jason"}},
+		{"no title", &Layout{NoTitle: true}, false, map[string]string{"A1": "姓名", "A2": "Jason"}},
+		{"multi-line title", &Layout{TitleRows: 2}, false, map[string]string{"A1": "People", "A3": "姓名", "A4": "Jason"}},
+		{"header row", &Layout{HeaderRow: 4}, true, map[string]string{"A1": "People", "A2": "", "A4": "姓名", "A6": "Jason"}},
+		{"offset and data row", &Layout{NoTitle: true, ColOffset: 2, DataRow: 4}, false,
+			map[string]string{"A1": "", "C1": "姓名", "D1": "年龄", "C2": "", "C4": "Jason", "D5": "25"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{&DefaultOption{SheetName: "people", Title: "People"}, tt.layout}
+			if tt.remind {
+				opts = append(opts, WithRemind())
+			}
+			data, err := Export(people, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			for cell, want := range tt.cells {
+				if got, _ := f.GetCellValue("people", cell); got != want {
+					t.Errorf("cell %s = %q, want %q", cell, got, want)
+				}
+			}
+			got, err := Import[codecPerson](bytes.NewReader(data), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, people) {
+				t.Errorf("Import() = %+v, want %+v", got, people)
+			}
+		})
+	}
+}
+
+func TestLayout_TitleMerge(t *testing.T) {
+	data, err := Export([]codecPerson{{Name: "Jason", Age: 20}},
+		&DefaultOption{SheetName: "people", Title: "人员名单\n2024 年"}, &Layout{TitleRows: 2, ColOffset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	merged, err := f.GetMergeCells("people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 1 || merged[0].GetStartAxis() != "B1" || merged[0].GetEndAxis() != "C2" {
+		t.Errorf("GetMergeCells() = %v, want B1:C2", merged)
+	}
+}
+
+func TestLayout_AutoHeader(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	rows := [][]any{
+		{"员工信息表"},
+		{"部门: 研发", "姓名"},
+		{},
+		{"序号", "姓名", "年龄"},
+		{1, "Jason", 20},
+		{2, "Jackson", 25},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow("Sheet1", cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[codecPerson](bytes.NewReader(buf.Bytes()), &Layout{AutoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+
+	type Other struct {
+		Code string `excel:"编码"`
+	}
+	if _, err := Import[Other](bytes.NewReader(buf.Bytes()), &Layout{AutoHeader: true}); err == nil {
+		t.Error("Import() want error when header row is not found")
+	}
+
+	// 没有标题的表格，表头在第 1 行
+	plain := excelize.NewFile()
+	defer plain.Close()
+	for i, row := range rows[3:] {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := plain.SetSheetRow("Sheet1", cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf, err = plain.WriteToBuffer(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = Import[codecPerson](bytes.NewReader(buf.Bytes()), &Layout{AutoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLayout_Overlap(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout *Layout
+	}{
+		{"header on title", &Layout{HeaderRow: 1}},
+		{"data on header", &Layout{HeaderRow: 3, DataRow: 3}},
+		{"negative offset", &Layout{ColOffset: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Export([]codecPerson{{Name: "Jason"}}, tt.layout); err == nil {
+				t.Error("Export() error = nil, want layout error")
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package go_excel
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 超链接列的样式
+var linkFont = &excelize.Font{Color: "#0563C1", Underline: "single"}
+
+// 解析标签 link，字段使用 excel:"主页,link" 时字段的值为链接地址，
+// excel:"名称,link=URL" 时显示字段的值，链接地址取自同一结构体中的 URL 字段，
+// 以 # 开头的地址为工作簿内的位置，如 #Sheet2!A1。
+// 每个工作表最多 65529 个超链接，超出后的单元格改用 HYPERLINK 公式
+func (c *Column) setLink(tag *columnTag) error {
+	_, hasValue := tag.value("link")
+	if !hasValue && !tag.flag("link") {
+		return nil
+	}
+	if c.IsImage || c.Formula != "" {
+		return errors.New(`option "link" cannot be used with "img" or "formula"`)
+	}
+	if !hasValue && !isStringType(c.FieldType) {
+		return fmt.Errorf("link field type must be string, got %s", c.FieldType)
+	}
+	c.Link = true
+	return nil
+}
+
+// 查找保存链接地址的字段，index 为所在结构体的字段索引
+func linkIndex(rt reflect.Type, index []int, name string) ([]int, error) {
+	sf, ok := rt.FieldByName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown link field %q", name)
+	}
+	if !isStringType(sf.Type) {
+		return nil, fmt.Errorf("link field %s must be string, got %s", name, sf.Type)
+	}
+	return append(append(make([]int, 0, len(index)+len(sf.Index)), index...), sf.Index...), nil
+}
+
+func isStringType(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt.Kind() == reflect.String
+}
+
+// 取出行中列的链接地址
+func linkTarget(rval reflect.Value, col *Column) string {
+	if !rval.IsValid() {
+		return ""
+	}
+	index := col.LinkIndex
+	if index == nil {
+		index = col.FieldIndex
+	}
+	v, err := rval.FieldByIndexErr(index)
+	if err != nil {
+		return ""
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return v.String()
+}
+
+// 为单元格设置超链接，超出工作表的超链接数量上限时返回 false，由调用方改用公式
+func (e *Excel) setLink(cell, target string) (bool, error) {
+	if e.links >= excelize.TotalSheetHyperlinks {
+		return false, nil
+	}
+	e.links++
+	linkType := "External"
+	if strings.HasPrefix(target, "#") {
+		linkType, target = "Location", target[1:]
+	}
+	return true, e.File.SetCellHyperLink(e.Option.SheetName, cell, target, linkType)
+}
+
+// HYPERLINK 公式，工作簿内的位置保留 # 前缀，没有显示的内容时显示链接地址
+func linkFormula(target string, display any) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	if display == nil {
+		return fmt.Sprintf("HYPERLINK(%s)", quote(target))
+	}
+	return fmt.Sprintf("HYPERLINK(%s,%s)", quote(target), quote(fmt.Sprint(display)))
+}
+
+// 取出 HYPERLINK 公式的链接地址
+func parseLinkFormula(formula string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(formula, "="), `HYPERLINK("`)
+	if !ok {
+		return "", false
+	}
+	var target strings.Builder
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != '"' {
+			target.WriteByte(rest[i])
+			continue
+		}
+		// 两个双引号为转义的双引号
+		if i+1 < len(rest) && rest[i+1] == '"' {
+			target.WriteByte('"')
+			i++
+			continue
+		}
+		return target.String(), true
+	}
+	return "", false
+}
+
+// 关系文件中的关系
+type xmlRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// 工作簿中的工作表列表
+type xmlWorkbookSheets struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// 工作表 XML 中的超链接
+type xmlHyperlinks struct {
+	Hyperlinks []struct {
+		Ref      string `xml:"ref,attr"`
+		Location string `xml:"location,attr"`
+		RID      string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"hyperlink"`
+}
+
+// 打开压缩包中的文件，不存在时返回 nil
+func openPart(zr *zip.Reader, name string) (io.ReadCloser, error) {
+	for _, file := range zr.File {
+		if file.Name == name {
+			return file.Open()
+		}
+	}
+	return nil, nil
+}
+
+// 解码压缩包中的 XML 文件，不存在时 v 保持零值
+func decodePart(zr *zip.Reader, name string, v any) error {
+	rc, err := openPart(zr, name)
+	if err != nil || rc == nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// 读取关系文件，返回 Id / 文件路径，相对路径按 part 所在目录解析
+func readRels(zr *zip.Reader, part string) (map[string]string, error) {
+	var content xmlRelationships
+	relsPath := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+	if err := decodePart(zr, relsPath, &content); err != nil {
+		return nil, err
+	}
+	rels := make(map[string]string, len(content.Relationships))
+	for _, rel := range content.Relationships {
+		rels[rel.ID] = rel.Target
+	}
+	return rels, nil
+}
+
+// 关系的目标为文件时转换为压缩包中的路径
+func partPath(part, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(part), target)
+}
+
+// 读取工作表的超链接，返回单元格 / 链接地址，工作簿内的位置以 # 开头。
+// excelize 读取链接时不区分外部地址和工作簿内的位置，直接从压缩包中的工作表 XML 读取，
+// 逐个读取元素直到 <hyperlinks>，不把工作表加载到内存
+func sheetLinks(zr *zip.Reader, sheet string) (map[string]string, error) {
+	const workbookPath = "xl/workbook.xml"
+	var workbook xmlWorkbookSheets
+	if err := decodePart(zr, workbookPath, &workbook); err != nil {
+		return nil, err
+	}
+	wbRels, err := readRels(zr, workbookPath)
+	if err != nil {
+		return nil, err
+	}
+	var sheetPath string
+	for _, s := range workbook.Sheets {
+		if target, ok := wbRels[s.RID]; ok && strings.EqualFold(s.Name, sheet) {
+			sheetPath = partPath(workbookPath, target)
+		}
+	}
+	rc, err := openPart(zr, sheetPath)
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		return nil, fmt.Errorf("worksheet %s not found", sheet)
+	}
+	defer rc.Close()
+	var content xmlHyperlinks
+	d := xml.NewDecoder(rc)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "hyperlinks" {
+			if err := d.DecodeElement(&content, &start); err != nil {
+				return nil, err
+			}
+			break
+		}
+		// 跳过 sheetData 等元素的内容，只读取根元素下的元素
+		if start.Name.Local != "worksheet" {
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	links := make(map[string]string, len(content.Hyperlinks))
+	if len(content.Hyperlinks) == 0 {
+		return links, nil
+	}
+	rels, err := readRels(zr, sheetPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range content.Hyperlinks {
+		target := rels[link.RID]
+		if link.RID == "" && link.Location != "" {
+			target = "#" + link.Location
+		}
+		if target == "" {
+			continue
+		}
+		// 链接可以覆盖一个区域，如 A1:B2
+		first, last, _ := strings.Cut(link.Ref, ":")
+		if last == "" {
+			last = first
+		}
+		col1, row1, err := excelize.CellNameToCoordinates(first)
+		if err != nil {
+			return nil, err
+		}
+		col2, row2, err := excelize.CellNameToCoordinates(last)
+		if err != nil {
+			return nil, err
+		}
+		for r := row1; r <= row2; r++ {
+			for c := col1; c <= col2; c++ {
+				cell, _ := excelize.CoordinatesToCellName(c, r)
+				links[cell] = target
+			}
+		}
+	}
+	return links, nil
+}
+
+// 导入时读取单元格的超链接或 HYPERLINK 公式，有链接地址字段时写入该字段，否则以链接地址作为单元格的值
+func (e *Excel) readLink(f *excelize.File, links map[string]string, elem reflect.Value, col mappedColumn, val string, row int) (string, error) {
+	cell, err := excelize.CoordinatesToCellName(col.index+1, row)
+	if err != nil {
+		return val, err
+	}
+	target, ok := links[cell]
+	if !ok {
+		formula, err := f.GetCellFormula(e.Option.SheetName, cell)
+		if err != nil {
+			return val, err
+		}
+		if target, ok = parseLinkFormula(formula); !ok || target == "" {
+			return val, nil
+		}
+	}
+	if col.LinkIndex == nil {
+		return target, nil
+	}
+	field, err := fieldByIndexAlloc(elem, col.LinkIndex)
+	if err != nil {
+		return val, err
+	}
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	field.SetString(target)
+	return val, nil
+}
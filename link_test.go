@@ -0,0 +1,167 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type linkSite struct {
+	Name string `excel:"名称,link=URL"`
+	URL  string `excel:"-"`
+	Home string `excel:"主页,link"`
+}
+
+func TestExcel_Link(t *testing.T) {
+	rows := []linkSite{
+		{"example", "https://example.com", "https://example.com/home"},
+		{"", "#people!A1", ""},
+		{"plain", "", ""},
+	}
+	data, err := Export(rows, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tests := []struct {
+		cell   string
+		value  string
+		link   bool
+		target string
+	}{
+		{"A3", "example", true, "https://example.com"},
+		{"B3", "https://example.com/home", true, "https://example.com/home"},
+		{"A4", "people!A1", true, "people!A1"},
+		{"B4", "", false, ""},
+		{"A5", "plain", false, ""},
+	}
+	for _, tt := range tests {
+		if got, _ := f.GetCellValue("people", tt.cell); got != tt.value {
+			t.Errorf("%s = %q, want %q", tt.cell, got, tt.value)
+		}
+		ok, target, err := f.GetCellHyperLink("people", tt.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.link || target != tt.target {
+			t.Errorf("%s link = %v %q, want %v %q", tt.cell, ok, target, tt.link, tt.target)
+		}
+	}
+	if style := cellStyle(t, f, "A3"); style.Font == nil || style.Font.Underline != "single" {
+		t.Errorf("A3 style = %+v, want underlined link font", style)
+	}
+
+	// 导入时链接地址写回 URL 字段
+	got, err := Import[linkSite](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []linkSite{
+		{"example", "https://example.com", "https://example.com/home"},
+		{"people!A1", "#people!A1", ""},
+		{"plain", "", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
+
+func TestColumn_Link(t *testing.T) {
+	type UnknownField struct {
+		Name string `excel:"名称,link=URL"`
+	}
+	if _, err := NewCodec[UnknownField](); err == nil {
+		t.Error("NewCodec() want error for unknown link field")
+	}
+	type IntLink struct {
+		ID int `excel:"编号,link"`
+	}
+	if _, err := NewCodec[IntLink](); err == nil {
+		t.Error("NewCodec() want error for non-string link field")
+	}
+	type ImageLink struct {
+		Avatar string `excel:"头像,img,link"`
+	}
+	if _, err := NewCodec[ImageLink](); err == nil {
+		t.Error("NewCodec() want error for link on image column")
+	}
+}
+
+func TestExcel_LinkTypes(t *testing.T) {
+	// 外部地址中的 ! 不影响链接类型
+	rows := []linkSite{
+		{"mail", "mailto:ops!team@example.com", ""},
+		{"page", "https://example.com/#!/home", "#people!B3"},
+		{"cell", "#'my sheet'!A1", "file:///tmp/a!b.txt"},
+	}
+	data, err := Export(rows, &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[linkSite](bytes.NewReader(data), &DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+}
+
+func TestExcel_LinkFormula(t *testing.T) {
+	// 超出超链接数量上限后改用 HYPERLINK 公式
+	e := &Excel{Option: newOptions(&DefaultOption{SheetName: "people"}), File: excelize.NewFile()}
+	defer e.File.Close()
+	if err := e.parseFields(reflect.TypeOf(linkSite{})); err != nil {
+		t.Fatal(err)
+	}
+	cols := e.columns()
+	e.links = excelize.TotalSheetHyperlinks
+	vals, err := e.rowValues(cols, make([]int, len(cols)), nil, reflect.ValueOf(linkSite{"say \"hi\"", "https://example.com", "#people!A1"}), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []excelize.Cell{
+		{Value: "say \"hi\"", Formula: `HYPERLINK("https://example.com","say ""hi""")`},
+		{Value: "#people!A1", Formula: `HYPERLINK("#people!A1","#people!A1")`},
+	}
+	for k, cell := range want {
+		if !reflect.DeepEqual(vals[k], cell) {
+			t.Errorf("cell %d = %+v, want %+v", k, vals[k], cell)
+		}
+	}
+
+	// 导入时从公式读取链接地址
+	f := excelize.NewFile()
+	defer f.Close()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.SetRow("A2", []any{"名称", "主页"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.SetRow("A3", vals); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[linkSite](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRows := []linkSite{{"say \"hi\"", "https://example.com", "#people!A1"}}
+	if !reflect.DeepEqual(got, wantRows) {
+		t.Errorf("Import() = %+v, want %+v", got, wantRows)
+	}
+}
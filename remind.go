@@ -0,0 +1,68 @@
+package go_excel
+
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 提示行中直接列出的下拉选项的最大数量
+const maxRemindItems = 10
+
+// WithRemind 导出时在表头下方写入提示行，导入时跳过该行
+func WithRemind() Option {
+	return optionFunc(func(options *Options) {
+		options.ShowRemind = true
+	})
+}
+
+// 列的提示，标签设置了 remind 时直接使用，否则由必填、格式和数据验证生成
+func (e *Excel) remind(col *Column) string {
+	if col.Remind != "" {
+		return col.Remind
+	}
+	parts := make([]string, 0, 3)
+	if col.Required {
+		parts = append(parts, "必填")
+	}
+	switch valueType(col.FieldType) {
+	case timeType, durationType:
+		parts = append(parts, "格式: "+e.numFmt(col))
+	}
+	if col.Validation != nil {
+		if items := e.listItems(col); len(items) > 0 && len(items) <= maxRemindItems {
+			parts = append(parts, "可选: "+strings.Join(items, "/"))
+		} else {
+			parts = append(parts, col.Validation.Prompt)
+		}
+	}
+	return strings.Join(parts, "；")
+}
+
+// 下拉列表的选项，不是下拉列表或字典读取失败时返回 nil
+func (e *Excel) listItems(col *Column) []string {
+	if col.Validation.Type != excelize.DataValidationTypeList {
+		return nil
+	}
+	if col.Dict == "" {
+		return col.Validation.List
+	}
+	d, err := e.dict(col)
+	if err != nil {
+		return nil
+	}
+	return d.labels
+}
+
+// 写入提示行
+func (e *Excel) writeRemind(cols []*Column, row int) error {
+	style, err := e.newStyle(e.theme().Remind)
+	if err != nil {
+		return err
+	}
+	vals := make([]any, len(cols))
+	for k, col := range cols {
+		vals[k] = excelize.Cell{Value: e.remind(col), StyleID: style}
+	}
+	return e.Sw.SetRow(e.cellName(0, row), vals)
+}
@@ -0,0 +1,97 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExcel_Remind(t *testing.T) {
+	type Row struct {
+		Name     string    `excel:"姓名,required"`
+		Birthday time.Time `excel:"生日,format=date"`
+		Status   int       `excel:"状态,dict=status"`
+		Gender   string    `excel:"性别,list=男|女,required"`
+		Age      int       `excel:"年龄,min=0,max=150"`
+		Phone    string    `excel:"电话,remind=11 位手机号"`
+		Note     string    `excel:"备注"`
+	}
+	rows := []Row{
+		{"Jason", time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local), 1, "男", 20, "13800000000", "note"},
+		{"Jackson", time.Date(1990, 5, 6, 0, 0, 0, 0, time.Local), 2, "女", 30, "13900000000", ""},
+	}
+	data, err := Export(rows, &DefaultOption{SheetName: "rows"}, WithRemind())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want := []string{
+		"必填",
+		"格式: yyyy-mm-dd",
+		"可选: 启用/停用/删除",
+		"必填；可选: 男/女",
+		"请输入 0 到 150 之间的整数",
+		"11 位手机号",
+		"",
+	}
+	for k, w := range want {
+		cell, _ := excelize.CoordinatesToCellName(k+1, 3)
+		if got, _ := f.GetCellValue("rows", cell); got != w {
+			t.Errorf("remind %s = %q, want %q", cell, got, w)
+		}
+	}
+	if got, _ := f.GetCellValue("rows", "A4"); got != "Jason" {
+		t.Errorf("first data row A4 = %q, want Jason", got)
+	}
+	dvs, err := f.GetDataValidations("rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dv := range dvs {
+		if dv.Sqref[1:] != "4:"+dv.Sqref[:1]+"5" {
+			t.Errorf("data validation on %s, want rows 4:5", dv.Sqref)
+		}
+	}
+
+	got, err := Import[Row](bytes.NewReader(data), &DefaultOption{SheetName: "rows"}, WithRemind())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Import() = %+v, want %+v", got, rows)
+	}
+}
+
+func TestImport_RemindLayout(t *testing.T) {
+	// 提示行在表头和数据之间，与旧版导入的布局一致
+	type Row struct {
+		Name string `excel:"姓名"`
+		Age  int    `excel:"年龄"`
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	for row, vals := range map[int][]any{1: {"title"}, 2: {"姓名", "年龄"}, 3: {"hint", "hint"}, 4: {"Jason", 25}} {
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := f.SetSheetRow("Sheet1", cell, &vals); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Import[Row](bytes.NewReader(buf.Bytes()), WithRemind())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Row{{"Jason", 25}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package go_excel
+
+import (
+	"io"
+	"iter"
+	"reflect"
+)
+
+// RowSource 拉取式数据源，Next 返回 false 表示没有更多数据
+type RowSource[T any] interface {
+	Next() (T, bool)
+}
+
+// 将数据源逐行写入工作簿后输出到 w
+func (c *Codec[T]) exportFrom(w io.Writer, next func() (T, bool)) error {
+	e := c.excel()
+	defer e.close()
+	err := e.writeFrom(func() (reflect.Value, bool) {
+		row, ok := next()
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(&row).Elem(), true
+	})
+	if err != nil {
+		return err
+	}
+	return e.File.Write(w)
+}
+
+// ExportSeq 从迭代器流式导出，不需要预先加载全部数据
+func (c *Codec[T]) ExportSeq(w io.Writer, seq iter.Seq[T]) error {
+	next, stop := iter.Pull(seq)
+	defer stop()
+	return c.exportFrom(w, next)
+}
+
+// ExportChan 从通道流式导出，直到通道关闭
+func (c *Codec[T]) ExportChan(w io.Writer, ch <-chan T) error {
+	return c.exportFrom(w, func() (T, bool) {
+		row, ok := <-ch
+		return row, ok
+	})
+}
+
+// ExportSource 从拉取式数据源流式导出
+func (c *Codec[T]) ExportSource(w io.Writer, src RowSource[T]) error {
+	return c.exportFrom(w, src.Next)
+}
+
+func ExportSeq[T any](w io.Writer, seq iter.Seq[T], opts ...Option) error {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return err
+	}
+	return c.ExportSeq(w, seq)
+}
+
+func ExportChan[T any](w io.Writer, ch <-chan T, opts ...Option) error {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return err
+	}
+	return c.ExportChan(w, ch)
+}
+
+func ExportSource[T any](w io.Writer, src RowSource[T], opts ...Option) error {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return err
+	}
+	return c.ExportSource(w, src)
+}
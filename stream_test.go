@@ -0,0 +1,66 @@
+package go_excel
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type counterSource struct {
+	n, max int
+}
+
+func (s *counterSource) Next() (codecPerson, bool) {
+	if s.n >= s.max {
+		return codecPerson{}, false
+	}
+	s.n++
+	return codecPerson{Name: "P", Age: s.n}, true
+}
+
+func TestExportStream(t *testing.T) {
+	people := []codecPerson{{Name: "Jason", Age: 20}, {Name: "Jackson", Age: 25}, {Name: "Tom", Age: 30}}
+	opt := &DefaultOption{SheetName: "people", Title: "People"}
+	tests := []struct {
+		name   string
+		export func(w io.Writer) error
+		want   []codecPerson
+	}{
+		{"seq", func(w io.Writer) error {
+			return ExportSeq(w, slices.Values(people), opt)
+		}, people},
+		{"chan", func(w io.Writer) error {
+			ch := make(chan codecPerson)
+			go func() {
+				defer close(ch)
+				for _, p := range people {
+					ch <- p
+				}
+			}()
+			return ExportChan(w, ch, opt)
+		}, people},
+		{"source", func(w io.Writer) error {
+			return ExportSource[codecPerson](w, &counterSource{max: 2}, opt)
+		}, []codecPerson{{Name: "P", Age: 1}, {Name: "P", Age: 2}}},
+		{"empty", func(w io.Writer) error {
+			return ExportSeq(w, slices.Values([]codecPerson{}), opt)
+		}, []codecPerson{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.export(&buf); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Import[codecPerson](&buf, opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Import() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package go_excel
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// CellStyler 由导出的数据类型实现，按字段名返回单元格样式，返回 nil 时使用行样式
+type CellStyler interface {
+	CellStyle(field string) *excelize.Style
+}
+
+var cellStylerType = reflect.TypeOf((*CellStyler)(nil)).Elem()
+
+// RowStyleFunc 按行返回数据行的样式，row 为导出的元素
+type RowStyleFunc func(row any, index int) *excelize.Style
+
+// StyleRow 按行设置数据行的样式，index 为数据行的序号，从 0 开始，返回 nil 时使用默认样式。
+// 行样式与 Excel.RowStyle、列的数字格式合并，内容相同的样式共用样式 ID。
+// 返回的样式按指针缓存其内容，导出期间不要修改已返回过的样式。
+// T 必须是导出的数据类型、其指针或它实现的接口，否则导出时返回错误
+func StyleRow[T any](fn func(row T, index int) *excelize.Style) Option {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	return optionFunc(func(options *Options) {
+		options.rowStyleType = typ
+		options.RowStyler = func(row any, index int) *excelize.Style {
+			switch v := row.(type) {
+			case T:
+				return fn(v, index)
+			case *T:
+				if v != nil {
+					return fn(*v, index)
+				}
+			}
+			return nil
+		}
+	})
+}
+
+// 合并后样式的缓存键，行样式和单元格样式按内容区分，每行返回新建的样式时缓存也不会增长
+type styleKey struct {
+	col  int
+	band *excelize.Style
+	row  string
+	cell string
+}
+
+// 一次导出中按行、按单元格设置的样式
+type styleCache struct {
+	bases    []*excelize.Style // 各列的基础样式
+	body     *excelize.Style   // 主题的数据行样式
+	altBody  *excelize.Style   // 主题的偶数数据行样式
+	rowStyle *excelize.Style   // Excel.RowStyle，未设置时为 nil
+	cell     bool              // 数据类型实现了 CellStyler
+	byKey    map[styleKey]int
+	byValue  map[string]int             // 合并后的样式内容 / 样式 ID，不同指针内容相同时共用
+	contents map[*excelize.Style]string // 行样式和单元格样式 / 样式内容，同一指针只序列化一次
+}
+
+// 没有任何行样式设置时返回 nil
+func (e *Excel) newStyleCache(cols []*Column) (*styleCache, error) {
+	if err := e.checkRowStyler(); err != nil {
+		return nil, err
+	}
+	c := &styleCache{
+		cell:     e.ModelRt != nil && reflect.PointerTo(e.ModelRt).Implements(cellStylerType),
+		byKey:    make(map[styleKey]int),
+		byValue:  make(map[string]int),
+		contents: make(map[*excelize.Style]string),
+	}
+	if !reflect.ValueOf(e.RowStyle).IsZero() {
+		rowStyle := e.RowStyle
+		c.rowStyle = &rowStyle
+	}
+	c.body, c.altBody = e.theme().Body, e.theme().AltBody
+	if c.altBody == nil {
+		c.altBody = c.body
+	}
+	if c.body == nil && c.altBody == nil && c.rowStyle == nil && e.Option.RowStyler == nil && !c.cell {
+		return nil, nil
+	}
+	c.bases = make([]*excelize.Style, len(cols))
+	for k, col := range cols {
+		c.bases[k] = e.columnStyle(col)
+	}
+	return c, nil
+}
+
+// 检查 StyleRow 的类型与导出的数据类型是否一致
+func (e *Excel) checkRowStyler() error {
+	typ := e.Option.rowStyleType
+	if typ == nil || e.ModelRt == nil {
+		return nil
+	}
+	if typ.Kind() == reflect.Interface {
+		if e.ModelRt.Implements(typ) || reflect.PointerTo(e.ModelRt).Implements(typ) {
+			return nil
+		}
+	} else if typ == e.ModelRt || typ == reflect.PointerTo(e.ModelRt) {
+		return nil
+	}
+	return fmt.Errorf("StyleRow expects %s, rows are %s", typ, e.ModelRt)
+}
+
+// 按主题、行样式和单元格样式设置一行单元格的样式 ID
+func (e *Excel) styleRow(cache *styleCache, cols []*Column, vals []any, item reflect.Value, index int) error {
+	var rowStyle *excelize.Style
+	if e.Option.RowStyler != nil {
+		rowStyle = e.Option.RowStyler(item.Interface(), index)
+	}
+	rowKey, err := cache.content(rowStyle)
+	if err != nil {
+		return err
+	}
+	var styler CellStyler
+	if cache.cell {
+		styler = cellStyler(item)
+	}
+	band := cache.body
+	if index%2 == 1 {
+		band = cache.altBody
+	}
+	for k, col := range cols {
+		var cellStyle *excelize.Style
+		if styler != nil {
+			cellStyle = styler.CellStyle(col.Field)
+		}
+		if band == nil && rowStyle == nil && cellStyle == nil && cache.rowStyle == nil {
+			continue
+		}
+		cellKey, err := cache.content(cellStyle)
+		if err != nil {
+			return err
+		}
+		key := styleKey{col: k, band: band, row: rowKey, cell: cellKey}
+		styleID, err := e.cachedStyle(cache, key, rowStyle, cellStyle)
+		if err != nil {
+			return err
+		}
+		cell := vals[k].(excelize.Cell)
+		cell.StyleID = styleID
+		vals[k] = cell
+	}
+	return nil
+}
+
+// 取出元素实现的 CellStyler，指针接收者的方法在元素不可寻址时使用副本
+func cellStyler(item reflect.Value) CellStyler {
+	if s, ok := item.Interface().(CellStyler); ok {
+		return s
+	}
+	rval := structValue(item)
+	if !rval.IsValid() {
+		return nil
+	}
+	if !rval.CanAddr() {
+		ptr := reflect.New(rval.Type())
+		ptr.Elem().Set(rval)
+		rval = ptr.Elem()
+	}
+	s, _ := rval.Addr().Interface().(CellStyler)
+	return s
+}
+
+// 样式的内容，作为缓存键，nil 时为空，先按指针查找，未命中时再序列化
+func (c *styleCache) content(style *excelize.Style) (string, error) {
+	if style == nil {
+		return "", nil
+	}
+	if s, ok := c.contents[style]; ok {
+		return s, nil
+	}
+	b, err := json.Marshal(style)
+	if err != nil {
+		return "", err
+	}
+	c.contents[style] = string(b)
+	return string(b), nil
+}
+
+// 依次合并列的基础样式、主题的数据行样式、Excel.RowStyle、行样式和单元格样式，返回缓存的样式 ID
+func (e *Excel) cachedStyle(cache *styleCache, key styleKey, rowStyle, cellStyle *excelize.Style) (int, error) {
+	if styleID, ok := cache.byKey[key]; ok {
+		return styleID, nil
+	}
+	style := &excelize.Style{}
+	for _, s := range []*excelize.Style{cache.bases[key.col], key.band, cache.rowStyle, rowStyle, cellStyle} {
+		mergeStyle(style, s)
+	}
+	b, err := json.Marshal(style)
+	if err != nil {
+		return 0, err
+	}
+	styleID, ok := cache.byValue[string(b)]
+	if !ok {
+		if styleID, err = e.File.NewStyle(style); err != nil {
+			return 0, err
+		}
+		cache.byValue[string(b)] = styleID
+	}
+	cache.byKey[key] = styleID
+	return styleID, nil
+}
+
+// 将 src 中设置了的部分覆盖到 dst
+func mergeStyle(dst, src *excelize.Style) {
+	if src == nil {
+		return
+	}
+	if len(src.Border) > 0 {
+		dst.Border = src.Border
+	}
+	if src.Fill.Type != "" {
+		dst.Fill = src.Fill
+	}
+	if src.Font != nil {
+		dst.Font = src.Font
+	}
+	if src.Alignment != nil {
+		dst.Alignment = src.Alignment
+	}
+	if src.Protection != nil {
+		dst.Protection = src.Protection
+	}
+	if src.NumFmt != 0 {
+		dst.NumFmt = src.NumFmt
+	}
+	if src.DecimalPlaces != nil {
+		dst.DecimalPlaces = src.DecimalPlaces
+	}
+	if src.CustomNumFmt != nil {
+		dst.CustomNumFmt = src.CustomNumFmt
+	}
+	dst.NegRed = dst.NegRed || src.NegRed
+}
@@ -0,0 +1,179 @@
+package go_excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type styleOrder struct {
+	No      string  `excel:"单号"`
+	Amount  float64 `excel:"金额,format=decimal"`
+	Overdue bool    `excel:"逾期"`
+}
+
+var styleBold = &excelize.Style{Font: &excelize.Font{Bold: true}}
+
+func (o *styleOrder) CellStyle(field string) *excelize.Style {
+	if field == "Amount" && o.Amount > 1000 {
+		return styleBold
+	}
+	return nil
+}
+
+func TestExcel_StyleRow(t *testing.T) {
+	red := &excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"#FFC7CE"}, Pattern: 1}}
+	orders := make([]styleOrder, 100)
+	for i := range orders {
+		orders[i] = styleOrder{No: "A", Amount: float64(i * 20), Overdue: i%2 == 1}
+	}
+	indexes := make([]int, 0, len(orders))
+	data, err := Export(orders, &DefaultOption{SheetName: "orders"}, StyleRow(func(o styleOrder, index int) *excelize.Style {
+		indexes = append(indexes, index)
+		if o.Overdue {
+			return red
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes) != len(orders) || indexes[99] != 99 {
+		t.Errorf("StyleRow called with %d indexes, want 0..99", len(indexes))
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	style := func(cell string) (int, *excelize.Style) {
+		id, err := f.GetCellStyle("orders", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := f.GetStyle(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id, s
+	}
+	tests := []struct {
+		cell    string
+		fill    bool
+		bold    bool
+		decimal bool
+	}{
+		{"A3", false, false, false}, // 第 0 行
+		{"A4", true, false, false},  // 第 1 行逾期
+		{"B4", true, false, true},
+		{"B53", false, false, true}, // 第 50 行，金额 1000
+		{"B54", true, true, true},   // 第 51 行逾期，金额 1020
+		{"B55", false, true, true},  // 第 52 行，金额 1040
+	}
+	for _, tt := range tests {
+		_, s := style(tt.cell)
+		if fill := len(s.Fill.Color) > 0 && s.Fill.Color[0] == "FFC7CE"; fill != tt.fill {
+			t.Errorf("%s fill = %v, want %v", tt.cell, s.Fill.Color, tt.fill)
+		}
+		if bold := s.Font != nil && s.Font.Bold; bold != tt.bold {
+			t.Errorf("%s bold = %v, want %v", tt.cell, bold, tt.bold)
+		}
+		if decimal := s.CustomNumFmt != nil && *s.CustomNumFmt == "0.00"; decimal != tt.decimal {
+			t.Errorf("%s number format = %v, want decimal %v", tt.cell, s.CustomNumFmt, tt.decimal)
+		}
+	}
+	// 相同的样式组合共用样式 ID
+	if a, _ := style("A4"); a != func() int { id, _ := style("A100"); return id }() {
+		t.Error("rows with the same style should share the style ID")
+	}
+}
+
+func TestExcel_RowStyleField(t *testing.T) {
+	e := New(&DefaultOption{SheetName: "orders"})
+	e.RowStyle = excelize.Style{Font: &excelize.Font{Italic: true}}
+	data, err := e.ExportToBytes([]styleOrder{{No: "A", Amount: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, cell := range []string{"A3", "B3"} {
+		id, _ := f.GetCellStyle("orders", cell)
+		s, err := f.GetStyle(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Font == nil || !s.Font.Italic {
+			t.Errorf("%s font = %+v, want italic", cell, s.Font)
+		}
+	}
+	if id, _ := f.GetCellStyle("orders", "A2"); id != 0 {
+		t.Errorf("header style = %d, want default", id)
+	}
+}
+
+func TestExcel_StyleCacheByContent(t *testing.T) {
+	e := &Excel{Option: newOptions(StyleRow(func(o styleOrder, index int) *excelize.Style {
+		// 每行新建内容相同的样式
+		return &excelize.Style{Font: &excelize.Font{Bold: index%2 == 0}}
+	})), File: excelize.NewFile()}
+	defer e.File.Close()
+	if err := e.parseFields(reflect.TypeOf(styleOrder{})); err != nil {
+		t.Fatal(err)
+	}
+	cols := e.columns()
+	cache, err := e.newStyleCache(cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := reflect.ValueOf(styleOrder{No: "A", Amount: 1})
+	for i := 0; i < 1000; i++ {
+		vals := make([]any, len(cols))
+		for k := range vals {
+			vals[k] = excelize.Cell{}
+		}
+		if err := e.styleRow(cache, cols, vals, item, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(cache.byKey) != 2*len(cols) {
+		t.Errorf("style cache has %d keys, want %d", len(cache.byKey), 2*len(cols))
+	}
+
+	// 同一指针的样式只序列化一次
+	cache.contents = make(map[*excelize.Style]string)
+	e.Option.RowStyler = func(row any, index int) *excelize.Style { return styleBold }
+	for i := 0; i < 100; i++ {
+		vals := make([]any, len(cols))
+		for k := range vals {
+			vals[k] = excelize.Cell{}
+		}
+		if err := e.styleRow(cache, cols, vals, item, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(cache.contents) != 1 {
+		t.Errorf("style cache has %d contents, want 1", len(cache.contents))
+	}
+}
+
+func TestStyleRow_TypeMismatch(t *testing.T) {
+	opt := StyleRow(func(p codecPerson, index int) *excelize.Style { return nil })
+	if _, err := NewCodec[styleOrder](opt); err == nil {
+		t.Error("NewCodec() want error for StyleRow of another type")
+	}
+	if _, err := Export([]styleOrder{{No: "A"}}, opt); err == nil {
+		t.Error("Export() want error for StyleRow of another type")
+	}
+	if _, err := NewCodec[*styleOrder](StyleRow(func(o *styleOrder, index int) *excelize.Style { return nil })); err != nil {
+		t.Errorf("NewCodec() error = %v, want nil for pointer rows", err)
+	}
+	if _, err := NewCodec[styleOrder](StyleRow(func(o CellStyler, index int) *excelize.Style { return nil })); err != nil {
+		t.Errorf("NewCodec() error = %v, want nil for implemented interface", err)
+	}
+}
@@ -0,0 +1,204 @@
+package go_excel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 标签选项的取值方式
+type tagKind int
+
+const (
+	tagFlag  tagKind = 1 << iota // 标识，如 required
+	tagValue                     // 键值，如 width=20
+)
+
+// 支持的标签选项
+var tagOptions = map[string]tagKind{
+	"name":     tagValue,
+	"width":    tagValue,
+	"format":   tagValue,
+	"default":  tagValue,
+	"prefix":   tagValue,
+	"group":    tagFlag | tagValue,
+	"required": tagFlag,
+	"img":      tagFlag,
+	"conv":     tagValue,
+	"dict":     tagValue,
+	"list":     tagValue,
+	"min":      tagValue,
+	"max":      tagValue,
+	"prompt":   tagValue,
+	"error":    tagValue,
+	"remind":   tagValue,
+	"example":  tagValue,
+	"alias":    tagValue,
+	"cond":     tagValue,
+	"agg":      tagValue,
+	"formula":  tagValue,
+	"link":     tagFlag | tagValue,
+}
+
+// 嵌套结构体字段上可以使用的选项
+var nestedTagOptions = map[string]bool{
+	"name":   true,
+	"prefix": true,
+	"group":  true,
+}
+
+// excel 标签的解析结果
+//
+// 标签由逗号分隔，第一项可以直接写表头，其余为 key=value 或标识：
+//
+//	excel:"姓名,width=20,required"
+//	excel:"name=First Name,format='#,##0.00',default=0"
+//
+// 含逗号的值用单引号括起来。不含逗号且空格后有小写字母或 key=value 的项时按旧的空格分隔格式解析，
+// 否则整个标签为表头，含小写单词的表头用 name= 指定：
+//
+//	excel:"头像地址 img"
+//	excel:"First Name"
+type columnTag struct {
+	Name   string
+	values map[string]string
+	flags  map[string]bool
+}
+
+func parseTag(tag string) (*columnTag, error) {
+	items, err := splitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	t := &columnTag{
+		values: make(map[string]string),
+		flags:  make(map[string]bool),
+	}
+	for i, item := range items {
+		key, value, hasValue := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if i == 0 && !hasValue {
+			// 第一项直接写表头
+			t.Name = unquoteTag(key)
+			continue
+		}
+		if key == "" {
+			return nil, fmt.Errorf("empty option in %q", tag)
+		}
+		kind, ok := tagOptions[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown option %q", key)
+		}
+		if _, ok := t.values[key]; ok || t.flags[key] {
+			return nil, fmt.Errorf("duplicate option %q", key)
+		}
+		switch {
+		case hasValue && kind&tagValue == 0:
+			return nil, fmt.Errorf("option %q does not take a value", key)
+		case !hasValue && kind&tagFlag == 0:
+			return nil, fmt.Errorf("option %q requires a value", key)
+		case hasValue:
+			t.values[key] = unquoteTag(strings.TrimSpace(value))
+		default:
+			t.flags[key] = true
+		}
+	}
+	if v, ok := t.values["name"]; ok {
+		if t.Name != "" {
+			return nil, fmt.Errorf("header given twice: %q and name=%q", t.Name, v)
+		}
+		t.Name = v
+	}
+	if t.Name == "" {
+		return nil, errors.New("missing header name")
+	}
+	return t, nil
+}
+
+// 按逗号拆分标签，单引号内的逗号不拆分。单引号只在一项的开头或 key= 之后表示引用，
+// 其他位置的单引号是普通字符，如 excel:"Owner's"
+func splitTag(tag string) ([]string, error) {
+	if fields := strings.Fields(tag); len(fields) > 1 && !strings.Contains(tag, ",") && isLegacyTag(fields) {
+		// 旧的空格分隔格式
+		return fields, nil
+	}
+	items := make([]string, 0)
+	var b strings.Builder
+	quoted := false
+	for _, r := range tag {
+		switch {
+		case r == '\'' && (quoted || quoteStart(b.String())):
+			quoted = !quoted
+			b.WriteRune(r)
+		case r == ',' && !quoted:
+			items = append(items, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", tag)
+	}
+	return append(items, strings.TrimSpace(b.String())), nil
+}
+
+// 旧的空格分隔格式：第一项为表头，之后有像选项的项，如 img、group=基本信息，
+// 未知的选项在解析时报错；都不像选项时整个标签为含空格的表头，如 excel:"First Name"
+func isLegacyTag(fields []string) bool {
+	if strings.Contains(fields[0], "=") {
+		return false
+	}
+	return slices.ContainsFunc(fields[1:], looksLikeOption)
+}
+
+// 含 = 或全部为小写字母的项像标签选项
+func looksLikeOption(field string) bool {
+	if strings.Contains(field, "=") {
+		return true
+	}
+	for _, r := range field {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// 单引号出现在一项的开头或 key= 之后时开始引用
+func quoteStart(prefix string) bool {
+	prefix = strings.TrimSpace(prefix)
+	return prefix == "" || strings.HasSuffix(prefix, "=") && strings.Count(prefix, "=") == 1
+}
+
+func unquoteTag(s string) string {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func (t *columnTag) value(key string) (string, bool) {
+	v, ok := t.values[key]
+	return v, ok
+}
+
+func (t *columnTag) flag(key string) bool {
+	return t.flags[key]
+}
+
+// 检查嵌套结构体字段上的选项
+func (t *columnTag) checkNested() error {
+	for key := range t.values {
+		if !nestedTagOptions[key] {
+			return fmt.Errorf("option %q is not supported on nested struct", key)
+		}
+	}
+	for key := range t.flags {
+		if !nestedTagOptions[key] {
+			return fmt.Errorf("option %q is not supported on nested struct", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package go_excel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        string
+		wantName   string
+		wantValues map[string]string
+		wantFlags  map[string]bool
+		wantErr    bool
+	}{
+		{"header", "姓名", "姓名", map[string]string{}, map[string]bool{}, false},
+		{"legacy", "头像地址 img", "头像地址", map[string]string{}, map[string]bool{"img": true}, false},
+		{"legacy value", "年龄 group=基本信息", "年龄", map[string]string{"group": "基本信息"}, map[string]bool{}, false},
+		{"grammar", "name=姓名,width=20,format=0.00,default=0,required,img", "姓名",
+			map[string]string{"name": "姓名", "width": "20", "format": "0.00", "default": "0"},
+			map[string]bool{"required": true, "img": true}, false},
+		{"positional", "姓名, width=20 ,required", "姓名",
+			map[string]string{"width": "20"}, map[string]bool{"required": true}, false},
+		{"space in header", "name=First Name", "First Name", map[string]string{"name": "First Name"}, map[string]bool{}, false},
+		{"quoted", "金额,format='#,##0.00'", "金额", map[string]string{"format": "#,##0.00"}, map[string]bool{}, false},
+		{"unknown key", "姓名,foo=1", "", nil, nil, true},
+		{"space header", "First Name", "First Name", map[string]string{}, map[string]bool{}, false},
+		{"legacy typo", "姓名 requried", "", nil, nil, true},
+		{"legacy unknown flag", "头像 img requried", "", nil, nil, true},
+		{"lowercase word header", "name=order id", "order id", map[string]string{"name": "order id"}, map[string]bool{}, false},
+		{"space header option", "First Name,required", "First Name", map[string]string{}, map[string]bool{"required": true}, false},
+		{"apostrophe", "Owner's", "Owner's", map[string]string{}, map[string]bool{}, false},
+		{"apostrophe value", "备注,remind=Owner's name,width=20", "备注",
+			map[string]string{"remind": "Owner's name", "width": "20"}, map[string]bool{}, false},
+		{"flag with value", "姓名,required=1", "", nil, nil, true},
+		{"value missing", "姓名,width", "", nil, nil, true},
+		{"duplicate", "姓名,width=1,width=2", "", nil, nil, true},
+		{"empty option", "姓名,,img", "", nil, nil, true},
+		{"no header", "width=20", "", nil, nil, true},
+		{"header twice", "姓名,name=名字", "", nil, nil, true},
+		{"unterminated", "金额,format='#,##0", "", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				t.Log(err)
+				return
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("parseTag() Name = %v, want %v", got.Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(got.values, tt.wantValues) {
+				t.Errorf("parseTag() values = %v, want %v", got.values, tt.wantValues)
+			}
+			if !reflect.DeepEqual(got.flags, tt.wantFlags) {
+				t.Errorf("parseTag() flags = %v, want %v", got.flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestExcel_parseFieldsTag(t *testing.T) {
+	type Address struct {
+		City string `excel:"城市"`
+	}
+	type Good struct {
+		Name    string  `excel:"name=姓名,width=30,required"`
+		Score   float64 `excel:"分数,format=0.00,default=60"`
+		Address Address `excel:"地址,prefix=住址"`
+		Skip    string  `excel:"-"`
+	}
+	type BadWidth struct {
+		Name string `excel:"姓名,width=abc"`
+	}
+	type BadDefault struct {
+		Age int `excel:"年龄,default=abc"`
+	}
+	type BadNested struct {
+		Address Address `excel:"地址,width=20"`
+	}
+	type BadPrefix struct {
+		Name string `excel:"姓名,prefix=a"`
+	}
+	tests := []struct {
+		name    string
+		model   any
+		wantErr bool
+	}{
+		{"good", Good{}, false},
+		{"bad width", BadWidth{}, true},
+		{"bad default", BadDefault{}, true},
+		{"bad nested", BadNested{}, true},
+		{"bad prefix", BadPrefix{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Excel{}
+			err := e.parseFields(reflect.TypeOf(tt.model))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				t.Log(err)
+				return
+			}
+			name := e.Fields["Name"]
+			if name.Width != 30 || !name.Required {
+				t.Errorf("Name column = %+v", name)
+			}
+			score := e.Fields["Score"]
+			if score.Format != "0.00" || score.Default != "60" {
+				t.Errorf("Score column = %+v", score)
+			}
+			if _, ok := e.Rows["住址城市"]; !ok {
+				t.Errorf("Rows = %v, want 住址城市", e.Rows)
+			}
+			if _, ok := e.Fields["Skip"]; ok {
+				t.Error("Skip column should be ignored")
+			}
+		})
+	}
+}
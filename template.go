@@ -0,0 +1,138 @@
+package go_excel
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 模板中未设置 ValidationRows 时预留的填写行数
+const defaultTemplateRows = 100
+
+// 标记模板示例行的名称，作用域为工作表，导入时跳过这些行
+const exampleRowsName = "_ExampleRows"
+
+// ExportTemplate 按标签生成空白导入模板，包含标题、表头、提示行、数据验证和示例行，
+// 表头被锁定，数据区域可以填写。examples 为空时按标签 example 生成一行示例，
+// 示例行在导入时跳过。生成后会按相同的选项导入一次，保证示例行可以导入
+func (c *Codec[T]) ExportTemplate(examples ...T) ([]byte, error) {
+	e := c.excel()
+	defer e.close()
+	e.template = true
+	if e.Option.ValidationRows <= 0 {
+		e.Option.ValidationRows = defaultTemplateRows
+	}
+	rows := reflect.ValueOf(examples)
+	if len(examples) == 0 {
+		example, err := e.exampleRows()
+		if err != nil {
+			return nil, err
+		}
+		rows = example
+	}
+	if err := e.write(rows); err != nil {
+		return nil, err
+	}
+	buf, err := e.File.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkTemplate(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("template cannot be imported: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func ExportTemplate[T any](opts ...Option) ([]byte, error) {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.ExportTemplate()
+}
+
+// 按标签 example 生成示例行，没有列设置 example 时不生成
+func (e *Excel) exampleRows() (reflect.Value, error) {
+	rows := reflect.MakeSlice(reflect.SliceOf(e.ModelRt), 0, 1)
+	cols := e.columns()
+	has := false
+	for _, col := range cols {
+		has = has || col.Example != ""
+	}
+	if !has {
+		return rows, nil
+	}
+	elem := reflect.New(e.ModelRt).Elem()
+	for _, col := range cols {
+		if col.IsImage {
+			continue
+		}
+		if err := e.setField(elem, col, col.Example); err != nil {
+			return rows, fmt.Errorf("invalid example for %s: %w", col.NaturalName, err)
+		}
+	}
+	return reflect.Append(rows, elem), nil
+}
+
+// 按导入的流程读取一遍模板，示例行也要能导入
+func (c *Codec[T]) checkTemplate(data []byte) error {
+	e := c.excel()
+	f, zr, err := openSource(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	e.source = zr
+	defer f.Close()
+	e.keepExamples = true
+	return e.readRows(f, func(reflect.Value, int) error {
+		return nil
+	})
+}
+
+// 用工作表级的名称标记 first 到 last 的示例行
+func (e *Excel) markExampleRows(first, last int) error {
+	return e.File.SetDefinedName(&excelize.DefinedName{
+		Name:     exampleRowsName,
+		RefersTo: fmt.Sprintf("'%s'!$%d:$%d", strings.ReplaceAll(e.Option.SheetName, "'", "''"), first, last),
+		Scope:    e.Option.SheetName,
+	})
+}
+
+// 读取工作表中标记的示例行范围，没有标记时 last 为 0
+func (e *Excel) exampleRange(f *excelize.File) (first, last int) {
+	for _, dn := range f.GetDefinedName() {
+		if dn.Name != exampleRowsName || dn.Scope != e.Option.SheetName {
+			continue
+		}
+		ref := dn.RefersTo[strings.LastIndex(dn.RefersTo, "!")+1:]
+		from, to, ok := strings.Cut(strings.ReplaceAll(ref, "$", ""), ":")
+		if !ok {
+			return 0, 0
+		}
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil {
+			return 0, 0
+		}
+		return first, last
+	}
+	return 0, 0
+}
+
+// 锁定工作表，只有数据区域可以编辑
+func (e *Excel) protectSheet() error {
+	return e.File.ProtectSheet(e.Option.SheetName, &excelize.SheetProtectionOptions{
+		AutoFilter:          true,
+		DeleteRows:          true,
+		FormatColumns:       true,
+		FormatRows:          true,
+		InsertRows:          true,
+		SelectLockedCells:   true,
+		SelectUnlockedCells: true,
+		Sort:                true,
+	})
+}
@@ -0,0 +1,157 @@
+package go_excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type templateUser struct {
+	Name     string    `excel:"姓名,required,example=张三"`
+	Status   int       `excel:"状态,dict=status,example=启用"`
+	Age      int       `excel:"年龄,min=0,max=150,example=30"`
+	Birthday time.Time `excel:"生日,format=date,example=1990-01-02"`
+	Note     string    `excel:"备注"`
+}
+
+func TestExportTemplate(t *testing.T) {
+	opts := []Option{&DefaultOption{SheetName: "users", Title: "用户导入"}, WithRemind(), WithValidationRows(50)}
+	data, err := ExportTemplate[templateUser](opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for cell, want := range map[string]string{
+		"A1": "用户导入", "A2": "姓名", "E2": "备注", "A3": "必填",
+		"A4": "张三", "B4": "启用", "C4": "30", "D4": "1990-01-02", "E4": "",
+	} {
+		if got, _ := f.GetCellValue("users", cell); got != want {
+			t.Errorf("cell %s = %q, want %q", cell, got, want)
+		}
+	}
+
+	locked := func(cell string) bool {
+		id, err := f.GetCellStyle("users", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		style, err := f.GetStyle(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return style.Protection == nil || style.Protection.Locked
+	}
+	if !locked("A2") || !locked("A3") {
+		t.Error("header and remind cells should be locked")
+	}
+	if locked("A4") || locked("E4") {
+		t.Error("example cells should not be locked")
+	}
+	if !worksheetContains(t, data, "<sheetProtection") {
+		t.Error("sheet is not protected")
+	}
+
+	dvs, err := f.GetDataValidations("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dv := range dvs {
+		if !strings.HasSuffix(dv.Sqref, "54") {
+			t.Errorf("data validation on %s, want rows 4:54", dv.Sqref)
+		}
+	}
+
+	// 示例行不导入，填写的行导入
+	got, err := Import[templateUser](bytes.NewReader(data), opts...)
+	if err != nil || len(got) != 0 {
+		t.Errorf("Import() = %+v, %v, want no rows", got, err)
+	}
+	if err := f.SetSheetRow("users", "A6", &[]any{"李四", "启用", 20, "", " "}); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = Import[templateUser](bytes.NewReader(buf.Bytes()), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []templateUser{{"李四", 1, 20, time.Time{}, " "}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCodec_ExportTemplate(t *testing.T) {
+	c, err := NewCodec[codecPerson](&DefaultOption{SheetName: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.ExportTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Import(bytes.NewReader(data))
+	if err != nil || len(got) != 0 {
+		t.Errorf("Import() = %+v, %v, want no rows", got, err)
+	}
+
+	examples := []codecPerson{{Name: "Jason", Age: 20}}
+	if data, err = c.ExportTemplate(examples...); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = c.Import(bytes.NewReader(data)); err != nil || len(got) != 0 {
+		t.Errorf("Import() = %+v, %v, want no rows", got, err)
+	}
+
+	type Required struct {
+		Name string `excel:"姓名,required"`
+		Age  int    `excel:"年龄,example=20"`
+	}
+	if _, err := ExportTemplate[Required](); err == nil {
+		t.Error("ExportTemplate() want error for required column without example")
+	}
+	if _, err := NewCodec[struct {
+		Age int `excel:"年龄,example=abc"`
+	}](); err == nil {
+		t.Error("NewCodec() want error for invalid example")
+	}
+}
+
+// 任一工作表的 XML 中包含 substr
+func worksheetContains(t *testing.T, data []byte, substr string) bool {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range zr.File {
+		if !strings.HasPrefix(file.Name, "xl/worksheets/") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), substr) {
+			return true
+		}
+	}
+	return false
+}
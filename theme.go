@@ -0,0 +1,231 @@
+package go_excel
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Theme 导出使用的一组样式，为 nil 的部分不设置样式
+type Theme struct {
+	Title       *excelize.Style // 标题
+	TitleHeight float64         // 标题的行高，0 时为 30
+	Group       *excelize.Style // 分组表头
+	Header      *excelize.Style // 表头
+	Body        *excelize.Style // 数据行
+	AltBody     *excelize.Style // 偶数数据行，为 nil 时使用 Body
+	Remind      *excelize.Style // 提示行
+	Footer      *excelize.Style // 合计行
+	TableStyle  string          // 表格样式，如 TableStyleMedium2，为空时表格不带样式
+}
+
+func (t *Theme) apply(options *Options) {
+	theme := *t
+	options.Theme = &theme
+}
+
+// 未设置主题时使用的样式
+var defaultTheme = DefaultTheme()
+
+// DefaultTheme 默认主题，蓝色标题和表格样式
+func DefaultTheme() *Theme {
+	return &Theme{
+		Title: &excelize.Style{
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+			Fill:      excelize.Fill{Type: "pattern", Color: []string{"#DFEBF6"}, Pattern: 1},
+			Font:      &excelize.Font{Bold: true, Size: 25},
+		},
+		Group: &excelize.Style{
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+			Font:      &excelize.Font{Bold: true},
+			Border:    borders("#9BC2E6"),
+		},
+		Remind: &excelize.Style{
+			Alignment: &excelize.Alignment{Vertical: "center", WrapText: true},
+			Fill:      excelize.Fill{Type: "pattern", Color: []string{"#FFF2CC"}, Pattern: 1},
+			Font:      &excelize.Font{Italic: true, Size: 9, Color: "#7F7F7F"},
+		},
+		Footer: &excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: []excelize.Border{{Type: "top", Color: "#5B9BD5", Style: 6}},
+		},
+		TableStyle: "TableStyleMedium2",
+	}
+}
+
+// PlainTheme 不带填充色的简洁主题，便于打印
+func PlainTheme() *Theme {
+	return &Theme{
+		Title: &excelize.Style{
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+			Font:      &excelize.Font{Bold: true, Size: 16},
+		},
+		Group: &excelize.Style{
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+			Font:      &excelize.Font{Bold: true},
+			Border:    borders("#000000"),
+		},
+		Header: &excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: []excelize.Border{{Type: "bottom", Color: "#000000", Style: 1}},
+		},
+		Remind: &excelize.Style{
+			Alignment: &excelize.Alignment{Vertical: "center", WrapText: true},
+			Font:      &excelize.Font{Italic: true, Size: 9, Color: "#7F7F7F"},
+		},
+		Footer: &excelize.Style{
+			Font:   &excelize.Font{Bold: true},
+			Border: []excelize.Border{{Type: "top", Color: "#000000", Style: 1}},
+		},
+	}
+}
+
+// BlueTheme 深蓝标题、蓝色表头、浅蓝隔行的主题
+func BlueTheme() *Theme {
+	return colorTheme("#1F4E78", "#2F75B5", "#BDD7EE", "#DDEBF7")
+}
+
+// GreenTheme 深绿标题、绿色表头、浅绿隔行的主题
+func GreenTheme() *Theme {
+	return colorTheme("#375623", "#548235", "#C6E0B4", "#E2EFDA")
+}
+
+// 按主色生成主题，依次为标题、表头、分组表头和合计行、隔行的填充色
+func colorTheme(title, header, group, band string) *Theme {
+	fill := func(color string) excelize.Fill {
+		return excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}
+	}
+	center := &excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	return &Theme{
+		Title: &excelize.Style{
+			Alignment: center,
+			Fill:      fill(title),
+			Font:      &excelize.Font{Bold: true, Size: 20, Color: "#FFFFFF"},
+		},
+		Group: &excelize.Style{Alignment: center, Fill: fill(group), Font: &excelize.Font{Bold: true}, Border: borders("#FFFFFF")},
+		Header: &excelize.Style{
+			Alignment: center,
+			Fill:      fill(header),
+			Font:      &excelize.Font{Bold: true, Color: "#FFFFFF"},
+			Border:    borders("#FFFFFF"),
+		},
+		AltBody: &excelize.Style{Fill: fill(band)},
+		Remind: &excelize.Style{
+			Alignment: &excelize.Alignment{Vertical: "center", WrapText: true},
+			Fill:      fill("#F2F2F2"),
+			Font:      &excelize.Font{Italic: true, Size: 9, Color: "#7F7F7F"},
+		},
+		Footer: &excelize.Style{Fill: fill(group), Font: &excelize.Font{Bold: true}},
+	}
+}
+
+// 四周的细边框
+func borders(color string) []excelize.Border {
+	return []excelize.Border{
+		{Type: "left", Color: color, Style: 1},
+		{Type: "right", Color: color, Style: 1},
+		{Type: "top", Color: color, Style: 1},
+		{Type: "bottom", Color: color, Style: 1},
+	}
+}
+
+// ThemeByName 按名称取内置主题：default、plain、blue、green
+func ThemeByName(name string) (*Theme, bool) {
+	switch name {
+	case "default":
+		return DefaultTheme(), true
+	case "plain":
+		return PlainTheme(), true
+	case "blue":
+		return BlueTheme(), true
+	case "green":
+		return GreenTheme(), true
+	}
+	return nil, false
+}
+
+// ThemeCells 参考单元格的位置，如 A1，为空的部分不读取
+type ThemeCells struct {
+	Sheet   string // 工作表，为空时使用第一个工作表
+	Title   string
+	Group   string
+	Header  string
+	Body    string
+	AltBody string
+	Remind  string
+	Footer  string
+}
+
+// LoadTheme 读取已有工作簿中参考单元格的样式作为主题，
+// 标题行高取标题单元格所在行，表格样式取工作表中第一个表格
+func LoadTheme(r io.Reader, cells ThemeCells) (*Theme, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sheet := cells.Sheet
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	if index, _ := f.GetSheetIndex(sheet); index == -1 {
+		return nil, fmt.Errorf("sheet %q not found", sheet)
+	}
+	theme := &Theme{}
+	for _, ref := range []struct {
+		cell  string
+		style **excelize.Style
+	}{
+		{cells.Title, &theme.Title},
+		{cells.Group, &theme.Group},
+		{cells.Header, &theme.Header},
+		{cells.Body, &theme.Body},
+		{cells.AltBody, &theme.AltBody},
+		{cells.Remind, &theme.Remind},
+		{cells.Footer, &theme.Footer},
+	} {
+		if ref.cell == "" {
+			continue
+		}
+		styleID, err := f.GetCellStyle(sheet, ref.cell)
+		if err != nil {
+			return nil, fmt.Errorf("cell %s: %w", ref.cell, err)
+		}
+		if *ref.style, err = f.GetStyle(styleID); err != nil {
+			return nil, fmt.Errorf("cell %s: %w", ref.cell, err)
+		}
+	}
+	if cells.Title != "" {
+		_, row, err := excelize.CellNameToCoordinates(cells.Title)
+		if err != nil {
+			return nil, err
+		}
+		if theme.TitleHeight, err = f.GetRowHeight(sheet, row); err != nil {
+			return nil, err
+		}
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) > 0 {
+		theme.TableStyle = tables[0].StyleName
+	}
+	return theme, nil
+}
+
+func (e *Excel) theme() *Theme {
+	if e.Option.Theme == nil {
+		return defaultTheme
+	}
+	return e.Option.Theme
+}
+
+// 创建样式，style 为 nil 时返回默认样式
+func (e *Excel) newStyle(style *excelize.Style) (int, error) {
+	if style == nil {
+		return 0, nil
+	}
+	return e.File.NewStyle(style)
+}
@@ -0,0 +1,159 @@
+package go_excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 导出并返回打开的工作簿
+func exportThemed(t *testing.T, opts ...Option) *excelize.File {
+	t.Helper()
+	people := []codecPerson{{"Jason", 20}, {"Jackson", 25}, {"Jayden", 30}}
+	data, err := Export(people, append([]Option{&DefaultOption{SheetName: "people", Title: "People"}}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func cellStyle(t *testing.T, f *excelize.File, cell string) *excelize.Style {
+	t.Helper()
+	id, err := f.GetCellStyle("people", cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	style, err := f.GetStyle(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return style
+}
+
+func fillColor(s *excelize.Style) string {
+	if len(s.Fill.Color) == 0 {
+		return ""
+	}
+	return s.Fill.Color[0]
+}
+
+func tableStyle(t *testing.T, f *excelize.File) string {
+	t.Helper()
+	tables, err := f.GetTables("people")
+	if err != nil || len(tables) != 1 {
+		t.Fatalf("GetTables() = %v, %v", tables, err)
+	}
+	return tables[0].StyleName
+}
+
+func TestTheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		title  string
+		header string
+		rows   [3]string
+		table  string
+	}{
+		{"default", nil, "DFEBF6", "", [3]string{"", "", ""}, "TableStyleMedium2"},
+		{"blue", []Option{BlueTheme()}, "1F4E78", "2F75B5", [3]string{"", "DDEBF7", ""}, ""},
+		{"custom", []Option{&Theme{
+			Body:    &excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"#EEEEEE"}, Pattern: 1}},
+			AltBody: &excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"#DDDDDD"}, Pattern: 1}},
+		}}, "", "", [3]string{"EEEEEE", "DDDDDD", "EEEEEE"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := exportThemed(t, tt.opts...)
+			if got := fillColor(cellStyle(t, f, "A1")); got != tt.title {
+				t.Errorf("title fill = %q, want %q", got, tt.title)
+			}
+			if got := fillColor(cellStyle(t, f, "B2")); got != tt.header {
+				t.Errorf("header fill = %q, want %q", got, tt.header)
+			}
+			for i, want := range tt.rows {
+				cell, _ := excelize.CoordinatesToCellName(2, i+3)
+				if got := fillColor(cellStyle(t, f, cell)); got != want {
+					t.Errorf("%s fill = %q, want %q", cell, got, want)
+				}
+			}
+			if got := tableStyle(t, f); got != tt.table {
+				t.Errorf("table style = %q, want %q", got, tt.table)
+			}
+		})
+	}
+}
+
+func TestThemeByName(t *testing.T) {
+	for _, name := range []string{"default", "plain", "blue", "green"} {
+		if theme, ok := ThemeByName(name); !ok || theme == nil {
+			t.Errorf("ThemeByName(%q) not found", name)
+		}
+	}
+	if _, ok := ThemeByName("pink"); ok {
+		t.Error("ThemeByName(pink) want not found")
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	ref := excelize.NewFile()
+	defer ref.Close()
+	styles := map[string]*excelize.Style{
+		"A1": {Font: &excelize.Font{Bold: true, Size: 18, Color: "#C00000"}},
+		"A2": {Fill: excelize.Fill{Type: "pattern", Color: []string{"#C00000"}, Pattern: 1}},
+		"A4": {Fill: excelize.Fill{Type: "pattern", Color: []string{"#FCE4D6"}, Pattern: 1}},
+	}
+	for cell, style := range styles {
+		id, err := ref.NewStyle(style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ref.SetCellStyle("Sheet1", cell, cell, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ref.SetRowHeight("Sheet1", 1, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := ref.AddTable("Sheet1", &excelize.Table{Range: "A2:B4", Name: "brand", StyleName: "TableStyleLight9"}); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := ref.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme, err := LoadTheme(buf, ThemeCells{Title: "A1", Header: "A2", Body: "A3", AltBody: "A4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.TitleHeight != 40 || theme.TableStyle != "TableStyleLight9" {
+		t.Errorf("LoadTheme() height = %v, table style = %q", theme.TitleHeight, theme.TableStyle)
+	}
+
+	f := exportThemed(t, theme)
+	if s := cellStyle(t, f, "A1"); s.Font == nil || s.Font.Color != "C00000" || s.Font.Size != 18 {
+		t.Errorf("title font = %+v", s.Font)
+	}
+	if got := fillColor(cellStyle(t, f, "A2")); got != "C00000" {
+		t.Errorf("header fill = %q, want C00000", got)
+	}
+	if got := fillColor(cellStyle(t, f, "A4")); got != "FCE4D6" {
+		t.Errorf("alternate row fill = %q, want FCE4D6", got)
+	}
+	if height, _ := f.GetRowHeight("people", 1); height != 40 {
+		t.Errorf("title height = %v, want 40", height)
+	}
+	if got := tableStyle(t, f); got != "TableStyleLight9" {
+		t.Errorf("table style = %q, want TableStyleLight9", got)
+	}
+
+	if _, err := LoadTheme(bytes.NewReader(buf.Bytes()), ThemeCells{Sheet: "missing"}); err == nil {
+		t.Error("LoadTheme() want error for missing sheet")
+	}
+}
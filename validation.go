@@ -0,0 +1,240 @@
+package go_excel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Validation 列的数据验证规则，由标签生成：
+//
+//	excel:"性别,list=男|女"            下拉列表
+//	excel:"年龄,min=0,max=150"         整数字段为整数范围，浮点字段为小数范围
+//	excel:"姓名,min=2,max=20"          字符串字段为文本长度
+//	excel:"入职日期,min=2000-01-01"     日期字段为日期范围
+//
+// prompt、error 分别设置选中单元格时的提示和输入错误时的提示，未设置时按规则生成
+type Validation struct {
+	Type   excelize.DataValidationType
+	List   []string // 下拉列表选项，字典列为空，导出时从字典读取
+	Min    string   // 最小值，日期为 DATE 公式
+	Max    string   // 最大值，日期为 DATE 公式
+	Prompt string   // 输入提示
+	Error  string   // 错误提示
+}
+
+// WithValidationRows 在数据下方额外的 n 个空行上也添加数据验证，便于作为模板填写
+func WithValidationRows(n int) Option {
+	return optionFunc(func(options *Options) {
+		options.ValidationRows = n
+	})
+}
+
+// 根据标签设置列的数据验证规则
+func (c *Column) setValidation(tag *columnTag) error {
+	list, hasList := tag.value("list")
+	minValue, hasMin := tag.value("min")
+	maxValue, hasMax := tag.value("max")
+	v := &Validation{}
+	switch {
+	case hasList && c.Dict != "":
+		return errors.New(`option "list" cannot be used with "dict"`)
+	case (hasMin || hasMax) && (hasList || c.Dict != ""):
+		return errors.New(`option "min" and "max" cannot be used with a list`)
+	case hasList:
+		v.Type = excelize.DataValidationTypeList
+		v.List = strings.Split(list, "|")
+		if len(strings.Join(v.List, ",")) > maxDropListLength {
+			return fmt.Errorf("list %q exceeds %d characters", list, maxDropListLength)
+		}
+	case c.Dict != "":
+		v.Type = excelize.DataValidationTypeList
+	case hasMin || hasMax:
+		if err := v.setRange(valueType(c.FieldType), minValue, maxValue); err != nil {
+			return err
+		}
+	}
+	prompt, hasPrompt := tag.value("prompt")
+	message, hasError := tag.value("error")
+	if v.Type == 0 {
+		if hasPrompt || hasError {
+			return errors.New(`option "prompt" and "error" require a validation rule`)
+		}
+		return nil
+	}
+	if !hasPrompt {
+		prompt = v.message()
+	}
+	if !hasError {
+		message = v.message()
+	}
+	v.Prompt, v.Error = prompt, message
+	c.Validation = v
+	return nil
+}
+
+// 按字段类型确定范围验证的类型并检查边界值
+func (v *Validation) setRange(typ reflect.Type, minValue, maxValue string) error {
+	var parse func(s string) (string, error)
+	switch {
+	case typ == timeType:
+		v.Type = excelize.DataValidationTypeDate
+		parse = func(s string) (string, error) {
+			t, err := convertStringToType(s, timeType)
+			if err != nil {
+				return "", err
+			}
+			y, m, d := t.(time.Time).Date()
+			return fmt.Sprintf("DATE(%d,%d,%d)", y, m, d), nil
+		}
+	case typ.Kind() == reflect.String:
+		v.Type = excelize.DataValidationTypeTextLength
+		parse = func(s string) (string, error) {
+			n, err := strconv.ParseUint(s, 10, 32)
+			return strconv.FormatUint(n, 10), err
+		}
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		v.Type = excelize.DataValidationTypeDecimal
+		parse = func(s string) (string, error) {
+			_, err := strconv.ParseFloat(s, 64)
+			return s, err
+		}
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		v.Type = excelize.DataValidationTypeWhole
+		parse = func(s string) (string, error) {
+			_, err := strconv.ParseInt(s, 10, 64)
+			return s, err
+		}
+	default:
+		return fmt.Errorf(`option "min" and "max" are not supported on %s field`, typ)
+	}
+	var err error
+	if minValue != "" {
+		if v.Min, err = parse(minValue); err != nil {
+			return fmt.Errorf("invalid min %q", minValue)
+		}
+	}
+	if maxValue != "" {
+		if v.Max, err = parse(maxValue); err != nil {
+			return fmt.Errorf("invalid max %q", maxValue)
+		}
+	}
+	if v.Min == "" && v.Max == "" {
+		return errors.New(`option "min" and "max" are empty`)
+	}
+	return nil
+}
+
+// 根据规则生成提示
+func (v *Validation) message() string {
+	if v.Type == excelize.DataValidationTypeList {
+		return "请从下拉列表中选择"
+	}
+	minValue, maxValue := v.Min, v.Max
+	unit := map[excelize.DataValidationType]string{
+		excelize.DataValidationTypeWhole:      "整数",
+		excelize.DataValidationTypeDecimal:    "数字",
+		excelize.DataValidationTypeDate:       "日期",
+		excelize.DataValidationTypeTextLength: "个字符",
+	}[v.Type]
+	if v.Type == excelize.DataValidationTypeDate {
+		minValue, maxValue = dateText(minValue), dateText(maxValue)
+	}
+	if v.Type == excelize.DataValidationTypeTextLength {
+		switch {
+		case minValue == "":
+			return fmt.Sprintf("最多输入 %s %s", maxValue, unit)
+		case maxValue == "":
+			return fmt.Sprintf("至少输入 %s %s", minValue, unit)
+		}
+		return fmt.Sprintf("请输入 %s 到 %s %s", minValue, maxValue, unit)
+	}
+	switch {
+	case minValue == "":
+		return fmt.Sprintf("请输入不大于 %s 的%s", maxValue, unit)
+	case maxValue == "":
+		return fmt.Sprintf("请输入不小于 %s 的%s", minValue, unit)
+	}
+	return fmt.Sprintf("请输入 %s 到 %s 之间的%s", minValue, maxValue, unit)
+}
+
+// 数据验证的边界，小数按数字传入，由 excelize 检查是否超出范围
+func (v *Validation) bound(s string) any {
+	if v.Type != excelize.DataValidationTypeDecimal {
+		return s
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return f
+}
+
+// 将 DATE(y,m,d) 转换为 y-mm-dd 显示
+func dateText(formula string) string {
+	var y, m, d int
+	if _, err := fmt.Sscanf(formula, "DATE(%d,%d,%d)", &y, &m, &d); err != nil {
+		return formula
+	}
+	return fmt.Sprintf("%d-%02d-%02d", y, m, d)
+}
+
+// 为数据区域添加数据验证
+func (e *Excel) addValidations(cols []*Column, firstRow, lastRow int) error {
+	for k, col := range cols {
+		if col.Validation == nil {
+			continue
+		}
+		colName := e.colName(k)
+		dv, err := e.dataValidation(col)
+		if err != nil {
+			return err
+		}
+		if dv == nil {
+			continue
+		}
+		dv.Sqref = fmt.Sprintf("%s%d:%s%d", colName, firstRow, colName, lastRow)
+		if err := e.File.AddDataValidation(e.Option.SheetName, dv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 按列的规则创建数据验证，字典为空时返回 nil
+func (e *Excel) dataValidation(col *Column) (*excelize.DataValidation, error) {
+	v := col.Validation
+	dv := excelize.NewDataValidation(true)
+	switch {
+	case col.Dict != "":
+		ok, err := e.setDictDropList(col, dv)
+		if err != nil || !ok {
+			return nil, err
+		}
+	case v.Type == excelize.DataValidationTypeList:
+		if err := dv.SetDropList(v.List); err != nil {
+			return nil, err
+		}
+	default:
+		var err error
+		switch {
+		case v.Min == "":
+			err = dv.SetRange(v.bound(v.Max), "", v.Type, excelize.DataValidationOperatorLessThanOrEqual)
+		case v.Max == "":
+			err = dv.SetRange(v.bound(v.Min), "", v.Type, excelize.DataValidationOperatorGreaterThanOrEqual)
+		default:
+			err = dv.SetRange(v.bound(v.Min), v.bound(v.Max), v.Type, excelize.DataValidationOperatorBetween)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("validation of %s: %w", col.NaturalName, err)
+		}
+	}
+	dv.SetInput(col.NaturalName, v.Prompt)
+	dv.SetError(excelize.DataValidationErrorStyleStop, col.NaturalName, v.Error)
+	return dv, nil
+}
@@ -0,0 +1,116 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExcel_Validation(t *testing.T) {
+	type Row struct {
+		Gender string    `excel:"性别,list=男|女"`
+		Age    int       `excel:"年龄,min=0,max=150,error=年龄超出范围"`
+		Score  float64   `excel:"分数,min=0.5"`
+		Name   string    `excel:"姓名,max=20,prompt=填写真实姓名"`
+		Joined time.Time `excel:"入职日期,min=2000-01-01,max=2030-12-31"`
+		Note   string    `excel:"备注"`
+	}
+	data, err := Export([]Row{{"男", 20, 1, "Jason", time.Now(), ""}}, &DefaultOption{SheetName: "rows"}, WithValidationRows(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dvs, err := f.GetDataValidations("rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]*excelize.DataValidation)
+	for _, dv := range dvs {
+		got[dv.Sqref] = dv
+	}
+	tests := []struct {
+		sqref    string
+		typ      string
+		operator string
+		formula1 string
+		formula2 string
+		prompt   string
+		error    string
+	}{
+		{"A3:A13", "list", "", `"男,女"`, "", "请从下拉列表中选择", "请从下拉列表中选择"},
+		{"B3:B13", "whole", "between", "0", "150", "请输入 0 到 150 之间的整数", "年龄超出范围"},
+		{"C3:C13", "decimal", "greaterThanOrEqual", "0.5", "", "请输入不小于 0.5 的数字", "请输入不小于 0.5 的数字"},
+		{"D3:D13", "textLength", "lessThanOrEqual", "20", "", "填写真实姓名", "最多输入 20 个字符"},
+		{"E3:E13", "date", "between", "DATE(2000,1,1)", "DATE(2030,12,31)",
+			"请输入 2000-01-01 到 2030-12-31 之间的日期", "请输入 2000-01-01 到 2030-12-31 之间的日期"},
+	}
+	if len(dvs) != len(tests) {
+		t.Errorf("GetDataValidations() returned %d rules, want %d", len(dvs), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.sqref, func(t *testing.T) {
+			dv, ok := got[tt.sqref]
+			if !ok {
+				t.Fatalf("no data validation on %s", tt.sqref)
+			}
+			if dv.Type != tt.typ || dv.Operator != tt.operator || dv.Formula1 != tt.formula1 || dv.Formula2 != tt.formula2 {
+				t.Errorf("rule = %s %s %q %q, want %s %s %q %q",
+					dv.Type, dv.Operator, dv.Formula1, dv.Formula2, tt.typ, tt.operator, tt.formula1, tt.formula2)
+			}
+			if dv.Prompt == nil || *dv.Prompt != tt.prompt {
+				t.Errorf("prompt = %v, want %q", dv.Prompt, tt.prompt)
+			}
+			if dv.Error == nil || *dv.Error != tt.error {
+				t.Errorf("error = %v, want %q", dv.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestExcel_ValidationBadTag(t *testing.T) {
+	type Bool struct {
+		Active bool `excel:"启用,min=1"`
+	}
+	type BadMin struct {
+		Age int `excel:"年龄,min=a"`
+	}
+	type ListRange struct {
+		Gender string `excel:"性别,list=男|女,max=1"`
+	}
+	type PromptOnly struct {
+		Name string `excel:"姓名,prompt=填写姓名"`
+	}
+	type ListDict struct {
+		Status int `excel:"状态,dict=status,list=1|2"`
+	}
+	if _, err := NewCodec[Bool](); err == nil {
+		t.Error("NewCodec() want error for range on bool")
+	}
+	if _, err := NewCodec[BadMin](); err == nil {
+		t.Error("NewCodec() want error for invalid min")
+	}
+	if _, err := NewCodec[ListRange](); err == nil {
+		t.Error("NewCodec() want error for list with range")
+	}
+	if _, err := NewCodec[PromptOnly](); err == nil {
+		t.Error("NewCodec() want error for prompt without rule")
+	}
+	if _, err := NewCodec[ListDict](); err == nil {
+		t.Error("NewCodec() want error for list with dict")
+	}
+
+	// 超出 Excel 范围的小数边界在导出时报错
+	type Huge struct {
+		Score float64 `excel:"分数,max=1e39"`
+	}
+	if _, err := Export([]Huge{{1}}); !errors.Is(err, excelize.ErrDataValidationRange) {
+		t.Errorf("Export() error = %v, want %v", err, excelize.ErrDataValidationRange)
+	}
+}
@@ -0,0 +1,114 @@
+package go_excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/text/width"
+)
+
+// AutoWidthOption 根据表头和单元格内容自动计算列宽，标签设置了 width 的列不参与计算
+type AutoWidthOption struct {
+	Min    float64 // 最小列宽，默认 8
+	Max    float64 // 最大列宽，默认 60
+	Sample int     // 参与计算的数据行数，默认 100
+}
+
+func (o *AutoWidthOption) apply(options *Options) {
+	auto := *o
+	if auto.Min <= 0 {
+		auto.Min = 8
+	}
+	if auto.Max <= 0 {
+		auto.Max = 60
+	}
+	if auto.Sample <= 0 {
+		auto.Sample = 100
+	}
+	options.AutoWidth = &auto
+}
+
+// 开启自动列宽时先读取前 Sample 行用于计算，返回的 next 会重新从第一行开始
+func (e *Excel) sampleRows(next func() (reflect.Value, bool)) (func() (reflect.Value, bool), []reflect.Value) {
+	if e.Option.AutoWidth == nil {
+		return next, nil
+	}
+	sample := make([]reflect.Value, 0)
+	for len(sample) < e.Option.AutoWidth.Sample {
+		item, ok := next()
+		if !ok {
+			break
+		}
+		sample = append(sample, item)
+	}
+	i := 0
+	return func() (reflect.Value, bool) {
+		if i < len(sample) {
+			i++
+			return sample[i-1], true
+		}
+		return next()
+	}, sample
+}
+
+// 计算各列宽度，0 表示使用默认宽度
+func (e *Excel) columnWidths(cols []*Column, sample []reflect.Value) []float64 {
+	widths := make([]float64, len(cols))
+	for k, col := range cols {
+		switch {
+		case col.Width > 0:
+			widths[k] = col.Width
+		case e.Option.AutoWidth != nil:
+			widths[k] = e.autoWidth(col, sample)
+		case k < 4:
+			// 未开启自动列宽时前 4 列宽度为 20
+			widths[k] = 20
+		}
+	}
+	return widths
+}
+
+func (e *Excel) autoWidth(col *Column, sample []reflect.Value) float64 {
+	// 表头加粗，多留一个字符
+	n := textWidth(col.NaturalName) + 1
+	if !col.IsImage {
+		for _, item := range sample {
+			rfval, ok := fieldValue(structValue(item), col)
+			if !ok {
+				continue
+			}
+			cellValue, err := e.cellValue(col, rfval)
+			if err != nil {
+				continue
+			}
+			text := fmt.Sprint(cellValue)
+			if _, ok := cellValue.(time.Time); ok {
+				// 日期按显示格式的长度估算
+				text = e.numFmt(col)
+			}
+			n = max(n, textWidth(text))
+		}
+	}
+	opt := e.Option.AutoWidth
+	return min(max(float64(n)+2, opt.Min), opt.Max)
+}
+
+// 文本显示宽度，中日韩等全角字符按两个字符计算，多行文本取最长的一行
+func textWidth(s string) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		w := 0
+		for _, r := range line {
+			switch width.LookupRune(r).Kind() {
+			case width.EastAsianWide, width.EastAsianFullwidth:
+				w += 2
+			default:
+				w++
+			}
+		}
+		n = max(n, w)
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package go_excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_textWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"ascii", "Jason", 5},
+		{"cjk", "上海市", 6},
+		{"fullwidth", "（ＡＢ）", 8},
+		{"mixed", "A上", 3},
+		{"multi line", "ab\n上海市", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textWidth(tt.s); got != tt.want {
+				t.Errorf("textWidth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcel_AutoWidth(t *testing.T) {
+	type Row struct {
+		Name   string `excel:"姓名"`
+		City   string `excel:"城市"`
+		Remark string `excel:"备注"`
+		Code   string `excel:"编码,width=12"`
+		Note   string `excel:"说明"`
+	}
+	rows := []Row{
+		{"Jason", "上海", "a very long remark that goes past the maximum width", "A1", ""},
+		{"Jo", "乌鲁木齐市", "", "B2", ""},
+		{"Jacksonville Smith", "", "", "", ""},
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]float64
+	}{
+		{"default", nil, map[string]float64{"A": 20, "B": 20, "C": 20, "D": 12, "E": 9.140625}},
+		{"auto", []Option{&AutoWidthOption{Max: 30}}, map[string]float64{"A": 20, "B": 12, "C": 30, "D": 12, "E": 8}},
+		{"sample", []Option{&AutoWidthOption{Sample: 1}}, map[string]float64{"A": 8, "B": 8, "C": 53, "D": 12, "E": 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Export(rows, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			for col, want := range tt.want {
+				got, err := f.GetColWidth("Sheet1", col)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("GetColWidth(%s) = %v, want %v", col, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Workbook 多工作表工作簿，每个工作表可以使用不同的结构体类型和选项
+type Workbook struct {
+	File   *excelize.File
+	sheets []string // 已添加的工作表
+}
+
+func NewWorkbook() *Workbook {
+	return &Workbook{File: excelize.NewFile()}
+}
+
+// 写入一个工作表，第一个工作表设为活动工作表
+func (wb *Workbook) addSheet(e *Excel, next func() (reflect.Value, bool)) error {
+	for _, name := range wb.sheets {
+		if name == e.Option.SheetName {
+			return fmt.Errorf("sheet %q already added", name)
+		}
+	}
+	e.File = wb.File
+	index, err := e.writeSheet(next)
+	if err != nil {
+		return err
+	}
+	if len(wb.sheets) == 0 {
+		wb.File.SetActiveSheet(index)
+	}
+	wb.sheets = append(wb.sheets, e.Option.SheetName)
+	return nil
+}
+
+// AddSheet 使用 Codec 的选项添加一个工作表
+func (c *Codec[T]) AddSheet(wb *Workbook, rows []T) error {
+	return wb.addSheet(c.excel(), sliceRows(reflect.ValueOf(rows)))
+}
+
+// AddSheet 添加一个工作表，表名、标题等由 opts 指定
+func AddSheet[T any](wb *Workbook, rows []T, opts ...Option) error {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return err
+	}
+	return c.AddSheet(wb, rows)
+}
+
+// 删除新建文件时自带的 Sheet1
+func (wb *Workbook) finish() error {
+	if len(wb.sheets) == 0 {
+		return errors.New("workbook has no sheet")
+	}
+	for _, name := range wb.sheets {
+		if name == "Sheet1" {
+			return nil
+		}
+	}
+	if index, _ := wb.File.GetSheetIndex("Sheet1"); index == -1 {
+		return nil
+	}
+	if err := wb.File.DeleteSheet("Sheet1"); err != nil {
+		return err
+	}
+	// 删除工作表后活动工作表会偏移，重新设置为第一个添加的工作表
+	index, err := wb.File.GetSheetIndex(wb.sheets[0])
+	if err != nil {
+		return err
+	}
+	wb.File.SetActiveSheet(index)
+	return nil
+}
+
+func (wb *Workbook) WriteTo(w io.Writer) (int64, error) {
+	if err := wb.finish(); err != nil {
+		return 0, err
+	}
+	return wb.File.WriteTo(w)
+}
+
+func (wb *Workbook) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := wb.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (wb *Workbook) SaveAs(name string) error {
+	if err := wb.finish(); err != nil {
+		return err
+	}
+	return wb.File.SaveAs(name)
+}
+
+func (wb *Workbook) Close() error {
+	return wb.File.Close()
+}
+
+// SheetImport 多工作表导入时一个工作表的匹配规则和目标切片
+type SheetImport struct {
+	desc      string // 匹配规则的描述
+	exclusive bool   // 只匹配尚未被其他规则选中的工作表
+	match     func(f *excelize.File, index int, name string, e *Excel) bool
+	decode    func(e *Excel, f *excelize.File) error
+	excel     *Excel
+	err       error
+}
+
+func sheetImport[T any](desc string, dst *[]T, opts []Option,
+	match func(f *excelize.File, index int, name string, e *Excel) bool) SheetImport {
+	c, err := NewCodec[T](opts...)
+	if err != nil {
+		return SheetImport{desc: desc, err: err}
+	}
+	return SheetImport{
+		desc:  desc,
+		match: match,
+		excel: c.excel(),
+		decode: func(e *Excel, f *excelize.File) error {
+			return e.readRows(f, func(elem reflect.Value, _ int) error {
+				*dst = append(*dst, c.value(elem))
+				return nil
+			})
+		},
+	}
+}
+
+// SheetByName 按表名匹配工作表
+func SheetByName[T any](name string, dst *[]T, opts ...Option) SheetImport {
+	return sheetImport(name, dst, opts, func(_ *excelize.File, _ int, sheet string, _ *Excel) bool {
+		return sheet == name
+	})
+}
+
+// SheetByIndex 按工作表的顺序匹配，从 0 开始
+func SheetByIndex[T any](index int, dst *[]T, opts ...Option) SheetImport {
+	return sheetImport(fmt.Sprintf("#%d", index), dst, opts, func(_ *excelize.File, i int, _ string, _ *Excel) bool {
+		return i == index
+	})
+}
+
+// SheetByHeaders 匹配第一个包含 T 全部表头且未被其他规则选中的工作表
+func SheetByHeaders[T any](dst *[]T, opts ...Option) SheetImport {
+	s := sheetImport(fmt.Sprintf("headers of %T", *new(T)), dst, opts, func(f *excelize.File, _ int, sheet string, e *Excel) bool {
+		groups, header, err := e.readHeader(f, sheet)
+		if err != nil {
+			return false
+		}
+		cols, err := e.mapHeader(groups, header)
+		return err == nil && len(cols) == len(e.Fields)
+	})
+	s.exclusive = true
+	return s
+}
+
+// ImportSheets 只解析一次文件，按规则把各工作表分别导入到对应的切片
+func ImportSheets(r io.Reader, sheets ...SheetImport) error {
+	f, zr, err := openSource(r)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	list := f.GetSheetList()
+	claimed := make(map[string]bool)
+	errs := make(SheetErrors, 0)
+	for _, s := range sheets {
+		if s.err != nil {
+			errs = append(errs, &SheetError{Sheet: s.desc, Err: s.err})
+			continue
+		}
+		name := ""
+		for i, v := range list {
+			if s.exclusive && claimed[v] {
+				continue
+			}
+			if s.match(f, i, v, s.excel) {
+				name = v
+				break
+			}
+		}
+		if name == "" {
+			errs = append(errs, &SheetError{Sheet: s.desc, Err: errors.New("sheet not found")})
+			continue
+		}
+		claimed[name] = true
+		e := *s.excel
+		e.Option.SheetName = name
+		e.source = zr
+		if err := s.decode(&e, f); err != nil {
+			errs = append(errs, &SheetError{Sheet: name, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
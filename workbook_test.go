@@ -0,0 +1,150 @@
+package go_excel
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type wbOrder struct {
+	OrderNo  string `excel:"订单号"`
+	Customer string `excel:"客户"`
+}
+
+type wbCustomer struct {
+	Name string `excel:"名称"`
+	City string `excel:"城市"`
+}
+
+func TestWorkbook(t *testing.T) {
+	orders := []wbOrder{{"A001", "Jason"}, {"A002", "Tom"}}
+	customers := []*wbCustomer{{"Jason", "上海"}, {"Tom", "北京"}}
+
+	wb := NewWorkbook()
+	defer wb.Close()
+	if err := AddSheet(wb, orders, &DefaultOption{SheetName: "订单", Title: "订单"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddSheet(wb, customers, &DefaultOption{SheetName: "客户", Title: "客户"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddSheet(wb, customers, &DefaultOption{SheetName: "客户"}); err == nil {
+		t.Error("AddSheet() want error for duplicate sheet")
+	}
+	data, err := wb.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got := f.GetSheetList(); !reflect.DeepEqual(got, []string{"订单", "客户"}) {
+		t.Errorf("GetSheetList() = %v", got)
+	}
+	if got := f.GetSheetName(f.GetActiveSheetIndex()); got != "订单" {
+		t.Errorf("active sheet = %v, want 订单", got)
+	}
+	names := make(map[string]bool)
+	for _, sheet := range f.GetSheetList() {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, table := range tables {
+			if names[table.Name] {
+				t.Errorf("duplicate table name %s", table.Name)
+			}
+			names[table.Name] = true
+		}
+	}
+
+	gotOrders, err := Import[wbOrder](bytes.NewReader(data), &DefaultOption{SheetName: "订单"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotOrders, orders) {
+		t.Errorf("Import() = %+v, want %+v", gotOrders, orders)
+	}
+	gotCustomers, err := Import[wbCustomer](bytes.NewReader(data), &DefaultOption{SheetName: "客户"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotCustomers) != 2 || gotCustomers[1] != *customers[1] {
+		t.Errorf("Import() = %+v, want %+v", gotCustomers, customers)
+	}
+}
+
+func TestImportSheets(t *testing.T) {
+	orders := []wbOrder{{"A001", "Jason"}, {"A002", "Tom"}}
+	customers := []wbCustomer{{"Jason", "上海"}, {"Tom", "北京"}}
+	wb := NewWorkbook()
+	defer wb.Close()
+	if err := AddSheet(wb, customers, &DefaultOption{SheetName: "客户"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddSheet(wb, orders, &DefaultOption{SheetName: "订单"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := wb.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("match", func(t *testing.T) {
+		var byName []wbOrder
+		var byIndex []*wbOrder
+		var byHeaders []wbCustomer
+		err := ImportSheets(bytes.NewReader(data),
+			SheetByName("订单", &byName),
+			SheetByIndex(1, &byIndex),
+			SheetByHeaders(&byHeaders),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(byName, orders) || !reflect.DeepEqual(byHeaders, customers) {
+			t.Errorf("ImportSheets() = %+v, %+v", byName, byHeaders)
+		}
+		if len(byIndex) != 2 || *byIndex[0] != orders[0] {
+			t.Errorf("ImportSheets() orders = %+v", byIndex)
+		}
+
+		// 已被选中的工作表不再参与表头匹配
+		var again []wbOrder
+		err = ImportSheets(bytes.NewReader(data), SheetByName("订单", &byName), SheetByHeaders(&again))
+		if err == nil {
+			t.Error("ImportSheets() want error for claimed sheet")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		type Bad struct {
+			Name string `excel:"名称"`
+			Age  int    `excel:"城市"`
+		}
+		var bad []Bad
+		var missing []wbOrder
+		var ok []wbOrder
+		err := ImportSheets(bytes.NewReader(data),
+			SheetByName("客户", &bad),
+			SheetByName("不存在", &missing),
+			SheetByName("订单", &ok),
+		)
+		var sheetErrs SheetErrors
+		if !errors.As(err, &sheetErrs) || len(sheetErrs) != 2 {
+			t.Fatalf("ImportSheets() error = %v", err)
+		}
+		if sheetErrs[0].Sheet != "客户" || sheetErrs[1].Sheet != "不存在" {
+			t.Errorf("ImportSheets() error = %v", err)
+		}
+		if !reflect.DeepEqual(ok, orders) {
+			t.Errorf("ImportSheets() orders = %+v", ok)
+		}
+	})
+}